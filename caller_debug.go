@@ -0,0 +1,20 @@
+//go:build !release
+// +build !release
+
+package context
+
+import "runtime"
+
+// callerSiteSkip skips runtime.Callers, callerSite, withDeadline, and the
+// exported With* function to land on the caller creating the Context.
+const callerSiteSkip = 4
+
+// callerSite returns the program counter of the code creating a Context.
+func callerSite() uintptr {
+	var pcs [1]uintptr
+	if runtime.Callers(callerSiteSkip, pcs[:]) == 0 {
+		return 0
+	}
+
+	return pcs[0]
+}
@@ -0,0 +1,10 @@
+//go:build release
+// +build release
+
+package context
+
+// callerSite returns the program counter of the code creating a Context.
+// Creation sites are not tracked in release builds.
+func callerSite() uintptr {
+	return 0
+}
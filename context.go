@@ -48,11 +48,11 @@
 package context
 
 import (
+	// nolint:depguard // reason: translating errors of stdlib parents
+	gocontext "context"
 	"reflect"
 	"sync"
 	"time"
-
-	"github.com/wspowell/errors"
 )
 
 // A Context carries a deadline, a cancellation signal, and other values across
@@ -102,6 +102,8 @@ type Context interface {
 	// If Done is closed, Err returns a non-nil error explaining why:
 	// Canceled if the context was canceled
 	// or DeadlineExceeded if the context's deadline passed.
+	// Deadline errors are a *DeadlineError describing the context that
+	// expired; check them using errors.Is(err, DeadlineExceeded).
 	// After Err returns a non-nil error, successive calls to Err return the same error.
 	Err() error
 
@@ -155,7 +157,7 @@ type Context interface {
 
 // Canceled is the error returned by Context.Err when the context is canceled.
 // nolint:errname,gochecknoglobals // reason: golang source
-var Canceled = errors.New("context canceled")
+var Canceled error = newCodedError(CodeCanceled, "context canceled", gocontext.Canceled)
 
 // DeadlineExceeded is the error returned by Context.Err when the context's
 // deadline passes.
@@ -168,6 +170,27 @@ func (deadlineExceededError) Error() string   { return "context deadline exceede
 func (deadlineExceededError) Timeout() bool   { return true }
 func (deadlineExceededError) Temporary() bool { return true }
 
+func (deadlineExceededError) InternalCode() string { return CodeDeadlineExceeded }
+
+// Is reports whether target is the stdlib DeadlineExceeded.
+func (deadlineExceededError) Is(target error) bool {
+	return target == gocontext.DeadlineExceeded // nolint:errorlint // reason: identity check for errors.Is
+}
+
+// fromStdErr translates the cancelation errors of stdlib Contexts into the
+// errors of this package, so that parents of either kind cancel children
+// with errors that satisfy errors.Is(err, Canceled).
+func fromStdErr(err error) error {
+	switch err { // nolint:errorlint // reason: identity check of stdlib sentinels
+	case gocontext.Canceled:
+		return Canceled
+	case gocontext.DeadlineExceeded:
+		return DeadlineExceeded
+	}
+
+	return err
+}
+
 // An emptyCtx is never canceled, has no values, and has no deadline. It is not
 // struct{}, since vars of this type must have distinct addresses.
 type emptyCtx int
@@ -211,6 +234,13 @@ var (
 // initialization, and tests, and as the top-level Context for incoming
 // requests.
 func Background() Context {
+	if isTracking() {
+		root := newRootCtx()
+		root.localized = Localize(root)
+
+		return root.localized
+	}
+
 	return Localize(background)
 }
 
@@ -254,13 +284,19 @@ func propagateCancel(parent Context, child canceler) {
 	// nolint:ifshort // reason: golang source
 	done := parent.Done()
 	if done == nil {
+		if isTracking() {
+			if root, ok := parentRootCtx(parent); ok {
+				root.addChild(child)
+			}
+		}
+
 		return // parent is never canceled
 	}
 
 	select {
 	case <-done:
 		// parent is already canceled
-		child.cancel(false, parent.Err())
+		child.cancel(false, fromStdErr(parent.Err()))
 
 		return
 	default:
@@ -273,16 +309,17 @@ func propagateCancel(parent Context, child canceler) {
 			child.cancel(false, p.err)
 		} else {
 			if p.children == nil {
-				p.children = make(map[canceler]struct{})
+				p.children = make(map[canceler]uint64)
 			}
-			p.children[child] = struct{}{}
+			p.childSeq++
+			p.children[child] = p.childSeq
 		}
 		p.mu.Unlock()
 	} else {
 		go func() {
 			select {
 			case <-parent.Done():
-				child.cancel(false, parent.Err())
+				child.cancel(false, fromStdErr(parent.Err()))
 			case <-child.Done():
 			}
 		}()
@@ -320,6 +357,14 @@ func parentCancelCtx(parent Context) (*cancelCtx, bool) {
 
 // removeChild removes a context from its parent.
 func removeChild(parent Context, child canceler) {
+	if isTracking() {
+		if root, ok := parentRootCtx(parent); ok {
+			root.removeChild(child)
+
+			return
+		}
+	}
+
 	p, ok := parentCancelCtx(parent)
 	if !ok {
 		return
@@ -352,10 +397,13 @@ func init() {
 type cancelCtx struct {
 	Context
 
-	mu       sync.Mutex            // protects following fields
-	done     chan struct{}         // created lazily, closed by first cancel call
-	children map[canceler]struct{} // set to nil by the first cancel call
-	err      error                 // set to non-nil by the first cancel call
+	mu       sync.Mutex          // protects following fields
+	done     chan struct{}       // created lazily, closed by first cancel call
+	children map[canceler]uint64 // set to nil by the first cancel call, values order children by creation
+	childSeq uint64              // sequence of the last child added
+	err      error               // set to non-nil by the first cancel call
+
+	id uint64 // assigned lazily when observed by Tree
 }
 
 func (c *cancelCtx) Value(key any) any {
@@ -441,6 +489,12 @@ func (c *cancelCtx) cancel(removeFromParent bool, err error) {
 // Canceling this context releases resources associated with it, so code should
 // call cancel as soon as the operations running in this Context complete.
 func WithDeadline(parent Context, d time.Time) (Context, CancelFunc) {
+	return withDeadline(parent, d)
+}
+
+// withDeadline implements WithDeadline. It must be called directly by the
+// exported function so the creation site of the timerCtx can be recorded.
+func withDeadline(parent Context, d time.Time) (Context, CancelFunc) {
 	if parent == nil {
 		panic("cannot create context from nil parent")
 	}
@@ -451,11 +505,12 @@ func WithDeadline(parent Context, d time.Time) (Context, CancelFunc) {
 	c := &timerCtx{
 		cancelCtx: newCancelCtx(parent),
 		deadline:  d,
+		site:      callerSite(),
 	}
 	propagateCancel(parent, c)
 	dur := time.Until(d)
 	if dur <= 0 {
-		c.cancel(true, DeadlineExceeded) // deadline has already passed
+		c.cancel(true, c.deadlineExceeded()) // deadline has already passed
 
 		return c, func() { c.cancel(false, Canceled) }
 	}
@@ -463,7 +518,7 @@ func WithDeadline(parent Context, d time.Time) (Context, CancelFunc) {
 	defer c.mu.Unlock()
 	if c.err == nil {
 		c.timer = time.AfterFunc(dur, func() {
-			c.cancel(true, DeadlineExceeded)
+			c.cancel(true, c.deadlineExceeded())
 		})
 	}
 
@@ -478,6 +533,7 @@ type timerCtx struct {
 	timer *time.Timer // Under cancelCtx.mu.
 
 	deadline time.Time
+	site     uintptr // program counter of the WithDeadline caller, debug builds only
 }
 
 func (c *timerCtx) Deadline() (deadline time.Time, ok bool) {
@@ -490,6 +546,16 @@ func (c *timerCtx) String() string {
 		time.Until(c.deadline).String() + "])"
 }
 
+// deadlineExceeded returns the error reported when the deadline of c passes.
+func (c *timerCtx) deadlineExceeded() error {
+	return &DeadlineError{
+		deadline: c.deadline,
+		late:     time.Since(c.deadline),
+		name:     NamePath(c.cancelCtx.Context),
+		site:     c.site,
+	}
+}
+
 func (c *timerCtx) cancel(removeFromParent bool, err error) {
 	c.cancelCtx.cancel(false, err)
 	if removeFromParent {
@@ -515,7 +581,7 @@ func (c *timerCtx) cancel(removeFromParent bool, err error) {
 // 		return slowOperation(ctx)
 // 	}
 func WithTimeout(parent Context, timeout time.Duration) (Context, CancelFunc) {
-	return WithDeadline(parent, time.Now().Add(timeout))
+	return withDeadline(parent, time.Now().Add(timeout))
 }
 
 // WithValue returns a copy of parent in which the value associated with key is
@@ -570,6 +636,25 @@ func (c *valueCtx) Value(key any) any {
 	if c.key == key {
 		return c.val
 	}
+	if immutable, ok := key.(immutableKey); ok && c.key == immutable.key {
+		return c.val
+	}
 
 	return c.Context.Value(key)
 }
+
+// immutableKey wraps a key to look up its immutable value, skipping local values.
+type immutableKey struct {
+	key any
+}
+
+// ImmutableValue returns the value associated with key by WithValue, or nil if
+// there is none. Unlike Context.Value, local values set via WithLocalValue
+// are ignored, therefore the result is always safe to send across goroutines
+// and processes.
+//
+// Only values set by this package are found. Values set on a stdlib parent
+// Context are not visible.
+func ImmutableValue(ctx Context, key any) any {
+	return ctx.Value(immutableKey{key: key})
+}
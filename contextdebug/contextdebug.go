@@ -0,0 +1,159 @@
+// Package contextdebug serves the live Context trees of the running program
+// via its HTTP server in the format expected by humans and tools.
+//
+// Nothing is registered on import. Register the handler explicitly, which
+// enables Context tracking, on the mux of a server that is not exposed
+// publicly:
+//
+// 	contextdebug.Register(http.DefaultServeMux)
+//
+// Query parameters:
+//
+// 	format=json      render JSON instead of text
+// 	min_age=30s      only list roots at least as old as the duration
+// 	cancel=<id>      (POST only) cancel the Context with the given ID,
+// 	                 only if the handler was created using AllowCancel
+package contextdebug
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/wspowell/context"
+)
+
+// Path is the path the handler is registered at by Register.
+const Path = "/debug/contexts"
+
+// Option configures the handler.
+type Option func(*handler)
+
+// AllowCancel enables canceling Contexts by their ID using POST ?cancel=<id>.
+// Disabled by default, since it lets any client of the handler cancel any
+// Context of the program.
+func AllowCancel() Option {
+	return func(handler *handler) {
+		handler.allowCancel = true
+	}
+}
+
+type handler struct {
+	allowCancel bool
+}
+
+// Register the handler on mux at Path.
+//
+// Context tracking is enabled as a side effect.
+func Register(mux *http.ServeMux, options ...Option) {
+	mux.Handle(Path, Handler(options...))
+}
+
+// Handler returns an HTTP handler that lists the live root Contexts created
+// by Background and the cancelable Contexts derived from them.
+//
+// Context tracking is enabled as a side effect.
+func Handler(options ...Option) http.Handler {
+	context.EnableTracking()
+
+	var config handler
+	for _, option := range options {
+		option(&config)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveContexts(w, r, config.allowCancel)
+	})
+}
+
+func serveContexts(w http.ResponseWriter, r *http.Request, allowCancel bool) {
+	query := r.URL.Query()
+
+	if cancelParam := query.Get("cancel"); cancelParam != "" {
+		if !allowCancel {
+			http.Error(w, "cancel is not allowed", http.StatusForbidden)
+
+			return
+		}
+		serveCancel(w, r, cancelParam)
+
+		return
+	}
+
+	var minAge time.Duration
+	if minAgeParam := query.Get("min_age"); minAgeParam != "" {
+		var err error
+		if minAge, err = time.ParseDuration(minAgeParam); err != nil {
+			http.Error(w, "invalid min_age: "+err.Error(), http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	nodes := Nodes(minAge)
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if query.Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if err := (context.Node{Kind: "roots", Children: nodes}).WriteJSON(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, node := range nodes {
+		if err := node.WriteText(w); err != nil {
+			return
+		}
+	}
+}
+
+func serveCancel(w http.ResponseWriter, r *http.Request, cancelParam string) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "cancel requires POST", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	id, err := strconv.ParseUint(cancelParam, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid cancel id: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if !context.CancelNode(id) {
+		http.Error(w, "no live context with id "+cancelParam, http.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("canceled " + cancelParam + "\n"))
+}
+
+// Nodes returns the trees of the live root Contexts at least minAge old,
+// oldest first.
+func Nodes(minAge time.Duration) []context.Node {
+	roots := context.Roots()
+
+	nodes := make([]context.Node, 0, len(roots))
+	for _, root := range roots {
+		node := context.Tree(root)
+		if node.Age < minAge {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Age > nodes[j].Age
+	})
+
+	return nodes
+}
@@ -0,0 +1,172 @@
+package contextdebug_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/contextdebug"
+)
+
+type requestKey struct{}
+
+func findRoot(t *testing.T, server *httptest.Server, query string, id uint64) (context.Node, bool) {
+	t.Helper()
+
+	response, err := http.Get(server.URL + "?format=json" + query)
+	assert.Nil(t, err)
+	defer response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	var list context.Node
+	assert.Nil(t, json.NewDecoder(response.Body).Decode(&list))
+
+	for _, root := range list.Children {
+		for _, child := range root.Children {
+			if child.ID == id {
+				return root, true
+			}
+		}
+	}
+
+	return context.Node{}, false
+}
+
+func Test_Handler(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(contextdebug.Handler())
+	defer server.Close()
+
+	ctx := context.WithValue(context.Background(), requestKey{}, "request")
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	id := context.Tree(ctx).ID
+
+	root, found := findRoot(t, server, "", id)
+	assert.True(t, found)
+	assert.Equal(t, "context.Background", root.Kind)
+	assert.Greater(t, root.Age, time.Duration(0))
+
+	var child context.Node
+	for _, node := range root.Children {
+		if node.ID == id {
+			child = node
+		}
+	}
+	assert.Equal(t, "WithDeadline", child.Kind)
+	assert.NotNil(t, child.Deadline)
+	assert.Equal(t, []string{"contextdebug_test.requestKey"}, child.Values)
+
+	_, found = findRoot(t, server, "&min_age=1h", id)
+	assert.False(t, found)
+
+	response, err := http.Get(server.URL)
+	assert.Nil(t, err)
+	defer response.Body.Close()
+	assert.True(t, strings.HasPrefix(response.Header.Get("Content-Type"), "text/plain"))
+
+	cancel()
+
+	_, found = findRoot(t, server, "", id)
+	assert.False(t, found)
+}
+
+func Test_Handler_cancel(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(contextdebug.Handler(contextdebug.AllowCancel()))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	id := strconv.FormatUint(context.Tree(ctx).ID, 10)
+
+	response, err := http.Get(server.URL + "?cancel=" + id)
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+	assert.Nil(t, ctx.Err())
+
+	response, err = http.Post(server.URL+"?cancel="+id, "text/plain", nil)
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	response, err = http.Post(server.URL+"?cancel="+id, "text/plain", nil)
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func Test_Handler_invalid_query(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(contextdebug.Handler())
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "?min_age=forever")
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response, err = http.Post(server.URL+"?cancel=abc", "text/plain", nil)
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	cancelServer := httptest.NewServer(contextdebug.Handler(contextdebug.AllowCancel()))
+	defer cancelServer.Close()
+
+	response, err = http.Post(cancelServer.URL+"?cancel=abc", "text/plain", nil)
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func Test_Handler_cancel_not_allowed(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(contextdebug.Handler())
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	response, err := http.Post(server.URL+"?cancel="+strconv.FormatUint(context.Tree(ctx).ID, 10), "text/plain", nil)
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Nil(t, ctx.Err())
+}
+
+func Test_Register(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	contextdebug.Register(mux)
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	response, err := http.Get(server.URL + contextdebug.Path)
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, err = http.Get(server.URL + "/other")
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
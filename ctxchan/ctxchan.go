@@ -0,0 +1,70 @@
+// Package ctxchan provides channel operations that stop when a Context is done.
+package ctxchan
+
+import (
+	"iter"
+
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+)
+
+// ErrClosed is returned by Recv when the channel is closed.
+var ErrClosed = errors.New("channel closed")
+
+// Send v on ch, blocking until it is received or ctx is done.
+// Returns ctx.Err() if v was not sent.
+func Send[T any](ctx context.Context, ch chan<- T, v T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case ch <- v:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Recv a value from ch, blocking until one is available or ctx is done.
+// Returns ErrClosed if ch is closed and ctx.Err() if ctx is done.
+func Recv[T any](ctx context.Context, ch <-chan T) (T, error) {
+	var zero T
+
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	select {
+	case value, ok := <-ch:
+		if !ok {
+			return zero, ErrClosed
+		}
+
+		return value, nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// Range returns an iterator over the values received from ch. Iteration stops
+// when ch is closed or ctx is done; check ctx.Err() afterwards to tell them
+// apart.
+//
+// 	for value := range ctxchan.Range(ctx, ch) {
+// 		...
+// 	}
+// 	if err := ctx.Err(); err != nil {
+// 		return err
+// 	}
+func Range[T any](ctx context.Context, ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			value, err := Recv(ctx, ch)
+			if err != nil || !yield(value) {
+				return
+			}
+		}
+	}
+}
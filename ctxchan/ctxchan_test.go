@@ -0,0 +1,88 @@
+package ctxchan_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/ctxchan"
+)
+
+func Test_Send(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan int, 1)
+	assert.NoError(t, ctxchan.Send(context.Background(), ch, 1))
+	assert.Equal(t, 1, <-ch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	ch <- 2
+	assert.ErrorIs(t, ctxchan.Send(ctx, ch, 3), context.DeadlineExceeded)
+
+	// Never sends once ctx is done, even if ch has room.
+	<-ch
+	assert.ErrorIs(t, ctxchan.Send(ctx, ch, 4), context.DeadlineExceeded)
+	assert.Empty(t, ch)
+}
+
+func Test_Recv(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan int, 1)
+	ch <- 1
+
+	value, err := ctxchan.Recv(context.Background(), ch)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err = ctxchan.Recv(ctx, ch)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(ch)
+	_, err = ctxchan.Recv(context.Background(), ch)
+	assert.ErrorIs(t, err, ctxchan.ErrClosed)
+}
+
+func Test_Range(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+
+	var values []int
+	for value := range ctxchan.Range(context.Background(), ch) {
+		values = append(values, value)
+	}
+	assert.Equal(t, []int{1, 2, 3}, values)
+}
+
+func Test_Range_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := make(chan int)
+	go func() {
+		ch <- 1
+		cancel()
+	}()
+
+	var values []int
+	for value := range ctxchan.Range(ctx, ch) {
+		values = append(values, value)
+	}
+	assert.Equal(t, []int{1}, values)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
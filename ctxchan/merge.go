@@ -0,0 +1,104 @@
+package ctxchan
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+// Merge forwards the values of every channel in chs to the returned channel,
+// which is closed once every channel in chs is closed or ctx is done.
+// The values are forwarded using gofunc.Run, one goroutine per channel.
+func Merge[T any](ctx context.Context, chs ...<-chan T) <-chan T {
+	merged := make(chan T)
+
+	var forwarders sync.WaitGroup
+	forwarders.Add(len(chs))
+	for _, ch := range chs {
+		gofunc.Run(ctx, func(ctx context.Context) error {
+			defer forwarders.Done()
+
+			for value := range Range(ctx, ch) {
+				if err := Send(ctx, merged, value); err != nil {
+					return err
+				}
+			}
+
+			return ctx.Err()
+		})
+	}
+
+	go func() {
+		forwarders.Wait()
+		close(merged)
+	}()
+
+	return merged
+}
+
+// Batch groups the values received from ch into slices of up to size values.
+// A batch is sent as soon as it is full, or maxWait after its first value was
+// received, if maxWait is positive. The returned channel is closed after the
+// last batch once ch is closed, or right away when ctx is done, dropping the
+// pending batch. Batches are assembled using gofunc.Run.
+func Batch[T any](ctx context.Context, ch <-chan T, size int, maxWait time.Duration) <-chan []T {
+	if size < 1 {
+		size = 1
+	}
+
+	batches := make(chan []T)
+
+	gofunc.Run(ctx, func(ctx context.Context) error {
+		defer close(batches)
+
+		var (
+			batch   []T
+			timer   = time.NewTimer(maxWait)
+			timeout <-chan time.Time
+		)
+		timer.Stop()
+
+		flush := func() error {
+			timer.Stop()
+			timeout = nil
+
+			if len(batch) == 0 {
+				return nil
+			}
+
+			full := batch
+			batch = nil
+
+			return Send(ctx, batches, full)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timeout:
+				if err := flush(); err != nil {
+					return err
+				}
+			case value, ok := <-ch:
+				if !ok {
+					return flush()
+				}
+
+				batch = append(batch, value)
+				if len(batch) >= size {
+					if err := flush(); err != nil {
+						return err
+					}
+				} else if len(batch) == 1 && maxWait > 0 {
+					timer.Reset(maxWait)
+					timeout = timer.C
+				}
+			}
+		}
+	})
+
+	return batches
+}
@@ -0,0 +1,105 @@
+package ctxchan_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/ctxchan"
+)
+
+func produce(values ...int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for _, value := range values {
+			ch <- value
+		}
+	}()
+
+	return ch
+}
+
+func Test_Merge(t *testing.T) {
+	t.Parallel()
+
+	merged := ctxchan.Merge(context.Background(), produce(1, 2), produce(3), produce())
+
+	var values []int
+	for value := range merged {
+		values = append(values, value)
+	}
+	sort.Ints(values)
+	assert.Equal(t, []int{1, 2, 3}, values)
+}
+
+func Test_Merge_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	open := make(chan int)
+	merged := ctxchan.Merge(ctx, open)
+	cancel()
+
+	select {
+	case _, ok := <-merged:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		assert.Fail(t, "merged channel not closed")
+	}
+}
+
+func Test_Batch(t *testing.T) {
+	t.Parallel()
+
+	batches := ctxchan.Batch(context.Background(), produce(1, 2, 3, 4, 5), 2, 0)
+
+	var collected [][]int
+	for batch := range batches {
+		collected = append(collected, batch)
+	}
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, collected)
+}
+
+func Test_Batch_maxWait(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan int)
+	batches := ctxchan.Batch(context.Background(), ch, 10, 20*time.Millisecond)
+
+	ch <- 1
+	ch <- 2
+
+	select {
+	case batch := <-batches:
+		assert.Equal(t, []int{1, 2}, batch)
+	case <-time.After(time.Second):
+		assert.Fail(t, "batch not flushed after maxWait")
+	}
+
+	close(ch)
+	_, ok := <-batches
+	assert.False(t, ok)
+}
+
+func Test_Batch_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := make(chan int)
+	batches := ctxchan.Batch(ctx, ch, 10, 0)
+	ch <- 1
+	cancel()
+
+	select {
+	case _, ok := <-batches:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		assert.Fail(t, "batches not closed")
+	}
+}
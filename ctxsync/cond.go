@@ -0,0 +1,89 @@
+package ctxsync
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/wspowell/context"
+)
+
+// Cond is a condition variable, like sync.Cond, but waiting can be abandoned
+// when a Context is done. A Cond must not be copied after first use.
+type Cond struct {
+	// L is held while observing or changing the condition.
+	L sync.Locker
+
+	mutex   sync.Mutex
+	waiters list.List
+}
+
+// NewCond creates a Cond using l.
+func NewCond(l sync.Locker) *Cond {
+	return &Cond{
+		L: l,
+	}
+}
+
+// Wait unlocks L, waits to be woken by Signal or Broadcast, and locks L again
+// before returning.
+func (self *Cond) Wait() {
+	_ = self.WaitCtx(uncancelable)
+}
+
+// WaitCtx unlocks L, waits to be woken by Signal or Broadcast or until ctx is
+// done, and locks L again before returning. Returns ctx.Err() if ctx was done
+// before being woken.
+func (self *Cond) WaitCtx(ctx context.Context) error {
+	woken := make(chan struct{}, 1)
+
+	self.mutex.Lock()
+	element := self.waiters.PushBack(woken)
+	self.mutex.Unlock()
+
+	self.L.Unlock()
+	defer self.L.Lock()
+
+	select {
+	case <-woken:
+		return nil
+	case <-ctx.Done():
+		self.mutex.Lock()
+		defer self.mutex.Unlock()
+
+		select {
+		case <-woken:
+			// Woken while ctx was done. Keep the wake up, so it is not lost.
+			return nil
+		default:
+			self.waiters.Remove(element)
+
+			return ctx.Err()
+		}
+	}
+}
+
+// Signal wakes one waiting goroutine, if any.
+func (self *Cond) Signal() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if next := self.waiters.Front(); next != nil {
+		self.wake(next)
+	}
+}
+
+// Broadcast wakes all waiting goroutines.
+func (self *Cond) Broadcast() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	for next := self.waiters.Front(); next != nil; next = self.waiters.Front() {
+		self.wake(next)
+	}
+}
+
+// wake the waiter of element. Requires the mutex to be held.
+func (self *Cond) wake(element *list.Element) {
+	self.waiters.Remove(element)
+	element.Value.(chan struct{}) <- struct{}{} // nolint:forcetypeassert // reason: only channels are queued
+}
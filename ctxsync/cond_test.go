@@ -0,0 +1,79 @@
+package ctxsync_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/ctxsync"
+)
+
+func Test_Cond(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+	cond := ctxsync.NewCond(&mutex)
+	ready := false
+
+	waited := make(chan error, 2)
+	for index := 0; index < 2; index++ {
+		go func() {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			for !ready {
+				if err := cond.WaitCtx(context.Background()); err != nil {
+					waited <- err
+
+					return
+				}
+			}
+			waited <- nil
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	mutex.Lock()
+	ready = true
+	cond.Broadcast()
+	mutex.Unlock()
+
+	assert.NoError(t, <-waited)
+	assert.NoError(t, <-waited)
+}
+
+func Test_Cond_WaitCtx_canceled(t *testing.T) {
+	t.Parallel()
+
+	var mutex ctxsync.Mutex
+	cond := ctxsync.NewCond(&mutex)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	mutex.Lock()
+	assert.ErrorIs(t, cond.WaitCtx(ctx), context.DeadlineExceeded)
+	// L is locked again.
+	assert.False(t, mutex.TryLock())
+	mutex.Unlock()
+
+	// The canceled waiter does not take the signal of another waiter.
+	woken := make(chan struct{})
+	go func() {
+		mutex.Lock()
+		cond.Wait()
+		mutex.Unlock()
+		close(woken)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cond.Signal()
+	select {
+	case <-woken:
+	case <-time.After(time.Second):
+		assert.Fail(t, "waiter not woken")
+	}
+}
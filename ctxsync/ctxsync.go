@@ -0,0 +1,17 @@
+// Package ctxsync provides synchronization primitives that can be abandoned
+// when a Context is done.
+//
+// Every blocking call has a variant taking a Context that returns ctx.Err()
+// once the Context is done, leaving the primitive as if the call had never
+// been made. No goroutines are started; waiters are woken directly.
+package ctxsync
+
+import (
+	"github.com/wspowell/context"
+)
+
+// uncancelable is the Context of the blocking calls that do not take one. It
+// is never done and, unlike context.Background, is not localized to the
+// calling goroutine, which would be costly on every call.
+// nolint:gochecknoglobals // reason: constant Context
+var uncancelable = context.TODO()
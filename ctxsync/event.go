@@ -0,0 +1,61 @@
+package ctxsync
+
+import (
+	"sync"
+
+	"github.com/wspowell/context"
+)
+
+// Event is a one-shot signal. Once set, it stays set and every waiter is
+// released. The zero value is an unset Event. An Event must not be copied
+// after first use.
+type Event struct {
+	once sync.Once
+	set  sync.Once
+	done chan struct{}
+}
+
+func (self *Event) channel() chan struct{} {
+	self.once.Do(func() {
+		self.done = make(chan struct{})
+	})
+
+	return self.done
+}
+
+// Set the Event, releasing every waiter. Setting an Event twice has no effect.
+func (self *Event) Set() {
+	self.set.Do(func() {
+		close(self.channel())
+	})
+}
+
+// IsSet reports whether the Event is set.
+func (self *Event) IsSet() bool {
+	select {
+	case <-self.channel():
+		return true
+	default:
+		return false
+	}
+}
+
+// Done returns a channel that is closed when the Event is set.
+func (self *Event) Done() <-chan struct{} {
+	return self.channel()
+}
+
+// Wait blocks until the Event is set or ctx is done.
+// Returns ctx.Err() if ctx was done first.
+func (self *Event) Wait(ctx context.Context) error {
+	if self.IsSet() {
+		return nil
+	}
+
+	select {
+	case <-self.channel():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
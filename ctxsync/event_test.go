@@ -0,0 +1,34 @@
+package ctxsync_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/ctxsync"
+)
+
+func Test_Event(t *testing.T) {
+	t.Parallel()
+
+	var event ctxsync.Event
+	assert.False(t, event.IsSet())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, event.Wait(ctx), context.DeadlineExceeded)
+
+	waited := make(chan error, 1)
+	go func() {
+		waited <- event.Wait(context.Background())
+	}()
+
+	event.Set()
+	event.Set()
+	assert.NoError(t, <-waited)
+	assert.True(t, event.IsSet())
+	assert.NoError(t, event.Wait(ctx))
+	<-event.Done()
+}
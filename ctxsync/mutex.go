@@ -0,0 +1,108 @@
+package ctxsync
+
+import (
+	"sync"
+
+	"github.com/wspowell/context"
+)
+
+// Mutex is a mutual exclusion lock whose acquisition can be abandoned when a
+// Context is done. The zero value is an unlocked Mutex.
+// A Mutex must not be copied after first use.
+type Mutex struct {
+	once      sync.Once
+	semaphore Semaphore
+}
+
+func (self *Mutex) sema() *Semaphore {
+	self.once.Do(func() {
+		self.semaphore.size = 1
+	})
+
+	return &self.semaphore
+}
+
+// Lock the Mutex, blocking until it is available.
+func (self *Mutex) Lock() {
+	_ = self.sema().Acquire(uncancelable, 1)
+}
+
+// LockCtx locks the Mutex, blocking until it is available or ctx is done.
+// Returns ctx.Err() if the Mutex was not locked.
+func (self *Mutex) LockCtx(ctx context.Context) error {
+	return self.sema().Acquire(ctx, 1)
+}
+
+// TryLock locks the Mutex if it is available and reports whether it did.
+func (self *Mutex) TryLock() bool {
+	return self.sema().TryAcquire(1)
+}
+
+// Unlock the Mutex. Panics if the Mutex is not locked.
+func (self *Mutex) Unlock() {
+	self.sema().Release(1)
+}
+
+// rwMutexMaxReaders is the weight of a writer, excluding every reader.
+const rwMutexMaxReaders = 1 << 30
+
+// RWMutex is a reader/writer mutual exclusion lock whose acquisition can be
+// abandoned when a Context is done. Lockers are served in order, so a waiting
+// writer blocks readers that arrive after it. The zero value is an unlocked
+// RWMutex. An RWMutex must not be copied after first use.
+type RWMutex struct {
+	once      sync.Once
+	semaphore Semaphore
+}
+
+func (self *RWMutex) sema() *Semaphore {
+	self.once.Do(func() {
+		self.semaphore.size = rwMutexMaxReaders
+	})
+
+	return &self.semaphore
+}
+
+// Lock the RWMutex for writing, blocking until it is available.
+func (self *RWMutex) Lock() {
+	_ = self.sema().Acquire(uncancelable, rwMutexMaxReaders)
+}
+
+// LockCtx locks the RWMutex for writing, blocking until it is available or
+// ctx is done. Returns ctx.Err() if the RWMutex was not locked.
+func (self *RWMutex) LockCtx(ctx context.Context) error {
+	return self.sema().Acquire(ctx, rwMutexMaxReaders)
+}
+
+// TryLock locks the RWMutex for writing if it is available and reports
+// whether it did.
+func (self *RWMutex) TryLock() bool {
+	return self.sema().TryAcquire(rwMutexMaxReaders)
+}
+
+// Unlock the RWMutex for writing.
+func (self *RWMutex) Unlock() {
+	self.sema().Release(rwMutexMaxReaders)
+}
+
+// RLock the RWMutex for reading, blocking until it is available.
+func (self *RWMutex) RLock() {
+	_ = self.sema().Acquire(uncancelable, 1)
+}
+
+// RLockCtx locks the RWMutex for reading, blocking until it is available or
+// ctx is done. Returns ctx.Err() if the RWMutex was not locked.
+func (self *RWMutex) RLockCtx(ctx context.Context) error {
+	return self.sema().Acquire(ctx, 1)
+}
+
+// TryRLock locks the RWMutex for reading if it is available and reports
+// whether it did.
+func (self *RWMutex) TryRLock() bool {
+	return self.sema().TryAcquire(1)
+}
+
+// RUnlock the RWMutex for reading.
+func (self *RWMutex) RUnlock() {
+	self.sema().Release(1)
+}
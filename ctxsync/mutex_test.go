@@ -0,0 +1,82 @@
+package ctxsync_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/ctxsync"
+)
+
+func Test_Mutex(t *testing.T) {
+	t.Parallel()
+
+	var mutex ctxsync.Mutex
+
+	mutex.Lock()
+	assert.False(t, mutex.TryLock())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, mutex.LockCtx(ctx), context.DeadlineExceeded)
+
+	locked := make(chan error, 1)
+	go func() {
+		locked <- mutex.LockCtx(context.Background())
+	}()
+
+	mutex.Unlock()
+	assert.NoError(t, <-locked)
+	mutex.Unlock()
+
+	assert.True(t, mutex.TryLock())
+	mutex.Unlock()
+
+	assert.Panics(t, mutex.Unlock)
+}
+
+func Test_RWMutex(t *testing.T) {
+	t.Parallel()
+
+	var mutex ctxsync.RWMutex
+
+	mutex.RLock()
+	assert.True(t, mutex.TryRLock())
+	assert.False(t, mutex.TryLock())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, mutex.LockCtx(ctx), context.DeadlineExceeded)
+
+	writer := make(chan error, 1)
+	go func() {
+		writer <- mutex.LockCtx(context.Background())
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// Readers queue behind the waiting writer.
+	assert.False(t, mutex.TryRLock())
+
+	mutex.RUnlock()
+	mutex.RUnlock()
+	assert.NoError(t, <-writer)
+
+	readCtx, readCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer readCancel()
+	assert.ErrorIs(t, mutex.RLockCtx(readCtx), context.DeadlineExceeded)
+
+	mutex.Unlock()
+	assert.NoError(t, mutex.RLockCtx(context.Background()))
+	mutex.RUnlock()
+}
+
+func Benchmark_Mutex(b *testing.B) {
+	var mutex ctxsync.Mutex
+
+	for i := 0; i < b.N; i++ {
+		mutex.Lock()
+		mutex.Unlock()
+	}
+}
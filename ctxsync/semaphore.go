@@ -0,0 +1,140 @@
+package ctxsync
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/wspowell/context"
+)
+
+type waiter struct {
+	weight int64
+	ready  chan struct{}
+}
+
+// Semaphore is a weighted semaphore. Waiters are served in the order they
+// called Acquire, so a large acquisition is not starved by smaller ones.
+type Semaphore struct {
+	mutex   sync.Mutex
+	size    int64
+	used    int64
+	waiters list.List
+}
+
+// NewSemaphore creates a Semaphore with a total weight of size.
+func NewSemaphore(size int64) *Semaphore {
+	return &Semaphore{
+		size: size,
+	}
+}
+
+// Acquire acquires the semaphore with a weight of n, blocking until resources
+// are available or ctx is done. On failure, ctx.Err() is returned and the
+// semaphore is left unchanged.
+//
+// If n exceeds the size of the semaphore, Acquire blocks until ctx is done.
+func (self *Semaphore) Acquire(ctx context.Context, n int64) error {
+	done := ctx.Done()
+
+	self.mutex.Lock()
+	select {
+	case <-done:
+		self.mutex.Unlock()
+
+		return ctx.Err()
+	default:
+	}
+
+	if self.size-self.used >= n && self.waiters.Len() == 0 {
+		self.used += n
+		self.mutex.Unlock()
+
+		return nil
+	}
+
+	if n > self.size {
+		self.mutex.Unlock()
+		<-done
+
+		return ctx.Err()
+	}
+
+	ready := make(chan struct{})
+	element := self.waiters.PushBack(waiter{
+		weight: n,
+		ready:  ready,
+	})
+	self.mutex.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-done:
+		self.mutex.Lock()
+		select {
+		case <-ready:
+			// Acquired after ctx was done; give it back.
+			self.used -= n
+			self.notifyWaiters()
+		default:
+			isFront := self.waiters.Front() == element
+			self.waiters.Remove(element)
+			// The waiters behind the front may fit now.
+			if isFront && self.size > self.used {
+				self.notifyWaiters()
+			}
+		}
+		self.mutex.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+// TryAcquire acquires the semaphore with a weight of n without blocking.
+// Reports whether the semaphore was acquired.
+func (self *Semaphore) TryAcquire(n int64) bool {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.size-self.used >= n && self.waiters.Len() == 0 {
+		self.used += n
+
+		return true
+	}
+
+	return false
+}
+
+// Release the semaphore with a weight of n.
+// Panics if more is released than is held.
+func (self *Semaphore) Release(n int64) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.used -= n
+	if self.used < 0 {
+		panic("ctxsync: semaphore released more than held")
+	}
+	self.notifyWaiters()
+}
+
+// notifyWaiters wakes the waiters, in order, that fit in the semaphore.
+// Requires the mutex to be held.
+func (self *Semaphore) notifyWaiters() {
+	for {
+		next := self.waiters.Front()
+		if next == nil {
+			return
+		}
+
+		waiting := next.Value.(waiter) // nolint:forcetypeassert // reason: only waiters are queued
+		if self.size-self.used < waiting.weight {
+			// Not enough room for the next waiter. Stop to keep the order.
+			return
+		}
+
+		self.used += waiting.weight
+		self.waiters.Remove(next)
+		close(waiting.ready)
+	}
+}
@@ -0,0 +1,81 @@
+package ctxsync_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/ctxsync"
+)
+
+func Test_Semaphore(t *testing.T) {
+	t.Parallel()
+
+	semaphore := ctxsync.NewSemaphore(3)
+
+	assert.NoError(t, semaphore.Acquire(context.Background(), 2))
+	assert.False(t, semaphore.TryAcquire(2))
+	assert.True(t, semaphore.TryAcquire(1))
+
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- semaphore.Acquire(context.Background(), 3)
+	}()
+
+	semaphore.Release(2)
+	select {
+	case <-acquired:
+		assert.Fail(t, "acquired while held")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	semaphore.Release(1)
+	assert.NoError(t, <-acquired)
+
+	assert.Panics(t, func() {
+		semaphore.Release(4)
+	})
+}
+
+func Test_Semaphore_Acquire_canceled(t *testing.T) {
+	t.Parallel()
+
+	semaphore := ctxsync.NewSemaphore(2)
+	assert.NoError(t, semaphore.Acquire(context.Background(), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// Blocks the smaller acquisition behind it, until it gives up.
+	assert.ErrorIs(t, semaphore.Acquire(ctx, 2), context.DeadlineExceeded)
+	assert.True(t, semaphore.TryAcquire(1))
+
+	assert.ErrorIs(t, semaphore.Acquire(ctx, 1), context.DeadlineExceeded)
+	assert.ErrorIs(t, semaphore.Acquire(ctx, 3), context.DeadlineExceeded)
+}
+
+func Test_Semaphore_Acquire_canceled_wakes_next(t *testing.T) {
+	t.Parallel()
+
+	semaphore := ctxsync.NewSemaphore(2)
+	assert.NoError(t, semaphore.Acquire(context.Background(), 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	large := make(chan error, 1)
+	go func() {
+		large <- semaphore.Acquire(ctx, 2)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	small := make(chan error, 1)
+	go func() {
+		small <- semaphore.Acquire(context.Background(), 1)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-large, context.Canceled)
+	assert.NoError(t, <-small)
+}
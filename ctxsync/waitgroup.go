@@ -0,0 +1,65 @@
+package ctxsync
+
+import (
+	"sync"
+
+	"github.com/wspowell/context"
+)
+
+// WaitGroup waits for a collection of goroutines to finish, like
+// sync.WaitGroup, but waiting can be abandoned when a Context is done.
+// The zero value is ready to use. A WaitGroup must not be copied after first use.
+type WaitGroup struct {
+	mutex   sync.Mutex
+	counter int
+	// done is closed when counter drops to zero.
+	done chan struct{}
+}
+
+// Add delta to the counter. Panics if the counter becomes negative.
+func (self *WaitGroup) Add(delta int) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.counter += delta
+	switch {
+	case self.counter < 0:
+		panic("ctxsync: negative WaitGroup counter")
+	case self.counter == 0:
+		if self.done != nil {
+			close(self.done)
+			self.done = nil
+		}
+	case self.done == nil:
+		self.done = make(chan struct{})
+	}
+}
+
+// Done decrements the counter by one.
+func (self *WaitGroup) Done() {
+	self.Add(-1)
+}
+
+// Wait blocks until the counter is zero.
+func (self *WaitGroup) Wait() {
+	_ = self.WaitCtx(uncancelable)
+}
+
+// WaitCtx blocks until the counter is zero or ctx is done.
+// Returns ctx.Err() if ctx was done first.
+func (self *WaitGroup) WaitCtx(ctx context.Context) error {
+	self.mutex.Lock()
+	done := self.done
+	self.mutex.Unlock()
+
+	if done == nil {
+		return nil
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
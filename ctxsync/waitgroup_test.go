@@ -0,0 +1,35 @@
+package ctxsync_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/ctxsync"
+)
+
+func Test_WaitGroup(t *testing.T) {
+	t.Parallel()
+
+	var group ctxsync.WaitGroup
+	group.Wait()
+
+	group.Add(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, group.WaitCtx(ctx), context.DeadlineExceeded)
+
+	go group.Done()
+	go group.Done()
+	assert.NoError(t, group.WaitCtx(context.Background()))
+
+	// Reusable once the counter is zero.
+	group.Add(1)
+	go group.Done()
+	group.Wait()
+
+	assert.Panics(t, group.Done)
+}
@@ -0,0 +1,93 @@
+package context
+
+import (
+	// nolint:depguard // reason: errors.Is compatibility with stdlib sentinels
+	gocontext "context"
+	"fmt"
+	"path"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// DeadlineError is the error returned by Context.Err when the deadline of a
+// Context passes. It describes the Context whose deadline expired, which is
+// not necessarily the Context Err was called on, since the error is
+// propagated to every child.
+//
+// DeadlineError satisfies errors.Is(err, DeadlineExceeded), as well as the
+// stdlib DeadlineExceeded.
+type DeadlineError struct {
+	deadline time.Time
+	late     time.Duration
+	name     string
+	site     uintptr
+}
+
+// Deadline of the Context that expired.
+func (self *DeadlineError) Deadline() time.Time {
+	return self.deadline
+}
+
+// Late returns how long after the deadline the expiration was detected.
+func (self *DeadlineError) Late() time.Duration {
+	return self.late
+}
+
+// Name returns the NamePath of the Context that expired, if named.
+func (self *DeadlineError) Name() string {
+	return self.name
+}
+
+// Site returns the location, as "file.go:line", of the WithDeadline or
+// WithTimeout call that created the Context that expired.
+// Always "" in release builds.
+func (self *DeadlineError) Site() string {
+	if self.site == 0 {
+		return ""
+	}
+
+	frames := runtime.CallersFrames([]uintptr{self.site})
+	frame, _ := frames.Next()
+	if frame.File == "" {
+		return ""
+	}
+
+	return path.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+}
+
+func (self *DeadlineError) Error() string {
+	if self.name != "" {
+		return "context deadline exceeded (" + self.name + ")"
+	}
+
+	return "context deadline exceeded"
+}
+
+func (self *DeadlineError) Timeout() bool   { return true }
+func (self *DeadlineError) Temporary() bool { return true }
+
+func (self *DeadlineError) InternalCode() string { return CodeDeadlineExceeded }
+
+// Is reports whether target is DeadlineExceeded or the stdlib DeadlineExceeded.
+func (self *DeadlineError) Is(target error) bool {
+	// nolint:errorlint // reason: identity check for errors.Is
+	return target == DeadlineExceeded || target == gocontext.DeadlineExceeded
+}
+
+// Format the error. The verb '%+v' includes the deadline, lateness, and
+// creation site of the Context that expired.
+func (self *DeadlineError) Format(state fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		fmt.Fprint(state, self.Error())
+		if state.Flag('+') || state.Flag('#') {
+			fmt.Fprintf(state, ": deadline %s exceeded by %s", self.deadline.Format(time.RFC3339Nano), self.late)
+			if site := self.Site(); site != "" {
+				fmt.Fprintf(state, ", created at %s", site)
+			}
+		}
+	default:
+		fmt.Fprint(state, self.Error())
+	}
+}
@@ -0,0 +1,44 @@
+//go:build !release
+// +build !release
+
+package context_test
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+)
+
+// nextLine returns "file.go:line" for the line after the caller.
+func nextLine() string {
+	_, file, line, _ := runtime.Caller(1)
+
+	return fmt.Sprintf("%s:%d", path.Base(file), line+1)
+}
+
+func Test_DeadlineError_Site(t *testing.T) {
+	t.Parallel()
+
+	site := nextLine()
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	var deadlineErr *context.DeadlineError
+	assert.True(t, errors.As(ctx.Err(), &deadlineErr))
+	assert.Equal(t, site, deadlineErr.Site())
+	assert.Contains(t, fmt.Sprintf("%+v", ctx.Err()), ", created at "+site)
+
+	site = nextLine()
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+
+	assert.True(t, errors.As(ctx.Err(), &deadlineErr))
+	assert.Equal(t, site, deadlineErr.Site())
+}
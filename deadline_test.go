@@ -0,0 +1,60 @@
+package context_test
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+)
+
+func Test_DeadlineError(t *testing.T) {
+	t.Parallel()
+
+	server, serverCancel := context.WithTimeout(context.WithName(context.Background(), "server"), time.Minute)
+	defer serverCancel()
+
+	database, databaseCancel := context.WithTimeout(context.WithName(server, "db"), time.Millisecond)
+	defer databaseCancel()
+
+	cache, cacheCancel := context.WithTimeout(database, time.Hour)
+	defer cacheCancel()
+
+	<-cache.Done()
+
+	err := cache.Err()
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, err, errors.Cause(err))
+
+	var deadlineErr *context.DeadlineError
+	assert.True(t, errors.As(err, &deadlineErr))
+	assert.Equal(t, "server/db", deadlineErr.Name())
+	expectedDeadline, _ := database.Deadline()
+	assert.Equal(t, expectedDeadline, deadlineErr.Deadline())
+	assert.GreaterOrEqual(t, deadlineErr.Late(), time.Duration(0))
+	assert.Equal(t, "context deadline exceeded (server/db)", err.Error())
+	assert.Contains(t, fmt.Sprintf("%+v", err), "context deadline exceeded (server/db): deadline ")
+
+	var netErr net.Error
+	assert.True(t, errors.As(err, &netErr))
+	assert.True(t, netErr.Timeout())
+
+	assert.Nil(t, server.Err())
+}
+
+func Test_DeadlineError_already_passed(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	var deadlineErr *context.DeadlineError
+	assert.True(t, errors.As(ctx.Err(), &deadlineErr))
+	assert.GreaterOrEqual(t, deadlineErr.Late(), time.Second)
+	assert.Equal(t, "", deadlineErr.Name())
+	assert.Equal(t, "context deadline exceeded", ctx.Err().Error())
+}
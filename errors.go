@@ -1,5 +1,75 @@
 package context
 
+import (
+	"fmt"
+
+	"github.com/wspowell/errors"
+)
+
+// Internal codes of the errors produced by this module, as returned by
+// InternalCode. This is the only place codes are defined; subpackages
+// reference these constants rather than declaring their own.
 const (
-	icContextCanceled = "CTX0"
+	CodeCanceled         = "CTX0" // context canceled
+	CodeDeadlineExceeded = "CTX1" // context deadline exceeded
+	CodeViolation        = "CTX2" // goroutine locality violation
+	CodeGofuncPanic      = "CTX3" // recovered gofunc panic
 )
+
+// InternalCode returns the stable internal code of the first error in the
+// chain of err that carries one, or "" if there is none.
+func InternalCode(err error) string {
+	var coded interface{ InternalCode() string }
+	if errors.As(err, &coded) {
+		return coded.InternalCode()
+	}
+
+	return ""
+}
+
+// codedError is an error created by errors.New that carries an internal code.
+// It is also considered equal to its optional stdlib equivalent by errors.Is.
+type codedError struct {
+	err   error
+	code  string
+	alias error
+}
+
+func newCodedError(code string, message string, alias error) *codedError {
+	return &codedError{
+		err:   errors.New("%s", message),
+		code:  code,
+		alias: alias,
+	}
+}
+
+func (self *codedError) Error() string {
+	return self.err.Error()
+}
+
+func (self *codedError) InternalCode() string {
+	return self.code
+}
+
+func (self *codedError) Is(target error) bool {
+	return self.alias != nil && target == self.alias // nolint:errorlint // reason: identity check for errors.Is
+}
+
+func (self *codedError) Unwrap() error {
+	return self.err
+}
+
+func (self *codedError) Format(state fmt.State, verb rune) {
+	if formatter, ok := self.err.(fmt.Formatter); ok { // nolint:errorlint // reason: type conversion, not an error check.
+		formatter.Format(state, verb)
+
+		return
+	}
+
+	fmt.Fprint(state, self.err.Error())
+}
+
+// violation returns the error a goroutine locality check panics with.
+func violation(message string) error {
+	return newCodedError(CodeViolation, message, nil)
+}
@@ -0,0 +1,128 @@
+package context_test
+
+import (
+	// nolint:depguard // reason: testing compatibility with stdlib sentinels
+	gocontext "context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+)
+
+func Test_InternalCode(t *testing.T) {
+	t.Parallel()
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	expiredCtx, expiredCancel := context.WithTimeout(context.Background(), -time.Second)
+	defer expiredCancel()
+
+	testCases := []struct {
+		about        string
+		err          error
+		expectedCode string
+	}{
+		{
+			about:        "canceled",
+			err:          canceledCtx.Err(),
+			expectedCode: "CTX0",
+		},
+		{
+			about:        "deadline exceeded sentinel",
+			err:          context.DeadlineExceeded,
+			expectedCode: "CTX1",
+		},
+		{
+			about:        "deadline exceeded",
+			err:          expiredCtx.Err(),
+			expectedCode: "CTX1",
+		},
+		{
+			about:        "wrapped",
+			err:          fmt.Errorf("request failed: %w", canceledCtx.Err()),
+			expectedCode: "CTX0",
+		},
+		{
+			about:        "wspowell wrapped",
+			err:          errors.Wrap(errors.New("db"), canceledCtx.Err()),
+			expectedCode: "CTX0",
+		},
+		{
+			about:        "foreign error",
+			err:          errors.New("other"),
+			expectedCode: "",
+		},
+		{
+			about:        "nil",
+			err:          nil,
+			expectedCode: "",
+		},
+	}
+
+	for index := range testCases {
+		testCase := testCases[index]
+		t.Run(testCase.about, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expectedCode, context.InternalCode(testCase.err))
+		})
+	}
+}
+
+func Test_InternalCode_unique(t *testing.T) {
+	t.Parallel()
+
+	codes := []string{
+		context.CodeCanceled,
+		context.CodeDeadlineExceeded,
+		context.CodeViolation,
+		context.CodeGofuncPanic,
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		assert.False(t, seen[code], "duplicate internal code %s", code)
+		assert.Regexp(t, `^CTX[0-9]+$`, code)
+		seen[code] = true
+	}
+}
+
+func Test_Canceled(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "context canceled", context.Canceled.Error())
+	assert.Equal(t, "context canceled", fmt.Sprintf("%v", context.Canceled))
+	assert.True(t, errors.Is(context.Canceled, context.Canceled))
+}
+
+func Test_errors_stdlib_compatible(t *testing.T) {
+	t.Parallel()
+
+	expiredCtx, expiredCancel := context.WithTimeout(context.Background(), -time.Second)
+	defer expiredCancel()
+
+	assert.True(t, errors.Is(context.Canceled, gocontext.Canceled))
+	assert.True(t, errors.Is(context.DeadlineExceeded, gocontext.DeadlineExceeded))
+	assert.True(t, errors.Is(expiredCtx.Err(), gocontext.DeadlineExceeded))
+	assert.False(t, errors.Is(context.Canceled, gocontext.DeadlineExceeded))
+}
+
+func Test_errors_stdlib_parent(t *testing.T) {
+	t.Parallel()
+
+	parent, parentCancel := gocontext.WithCancel(gocontext.Background())
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	parentCancel()
+	<-ctx.Done()
+
+	assert.Equal(t, context.Canceled, ctx.Err())
+	assert.Equal(t, "CTX0", context.InternalCode(ctx.Err()))
+}
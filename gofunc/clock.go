@@ -0,0 +1,46 @@
+package gofunc
+
+import (
+	"time"
+)
+
+// Clock tells time for Every, After, and Cron. Replace the system clock using
+// WithClock to control the timing of schedules in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a Timer that fires once duration elapsed.
+	NewTimer(duration time.Duration) Timer
+}
+
+// Timer is a single event of a Clock, see time.Timer.
+type Timer interface {
+	// C returns the channel that receives the time when the Timer fires.
+	C() <-chan time.Time
+	// Stop the Timer. Reports whether the Timer was stopped before it fired.
+	Stop() bool
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(duration time.Duration) Timer {
+	return systemTimer{
+		timer: time.NewTimer(duration),
+	}
+}
+
+type systemTimer struct {
+	timer *time.Timer
+}
+
+func (self systemTimer) C() <-chan time.Time {
+	return self.timer.C
+}
+
+func (self systemTimer) Stop() bool {
+	return self.timer.Stop()
+}
@@ -0,0 +1,202 @@
+package gofunc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wspowell/errors"
+)
+
+// ErrInvalidCron is returned by ParseCron for malformed expressions.
+var ErrInvalidCron = errors.New("invalid cron expression")
+
+// cronSearchYears bounds the search for the next time of a CronSchedule.
+const cronSearchYears = 5
+
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+// nolint:gochecknoglobals // reason: constant field definitions
+var (
+	cronMinute  = cronField{name: "minute", min: 0, max: 59}
+	cronHour    = cronField{name: "hour", min: 0, max: 23}
+	cronDay     = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth   = cronField{name: "month", min: 1, max: 12}
+	cronWeekday = cronField{name: "day of week", min: 0, max: 7}
+
+	cronDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// CronSchedule is a parsed cron expression.
+type CronSchedule struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	// Restricted day fields are matched if either matches, as in cron.
+	anyDay     bool
+	anyWeekday bool
+}
+
+// ParseCron parses a standard five field cron expression:
+//
+// 	minute hour day-of-month month day-of-week
+//
+// Fields are "*", a value, a range "a-b", or a list of them separated by ",",
+// each optionally followed by a step "/n". Day of week 0 and 7 are Sunday.
+// If both day of month and day of week are restricted, a day matching either
+// matches. The descriptors @yearly, @annually, @monthly, @weekly, @daily,
+// @midnight, and @hourly are also accepted.
+func ParseCron(expression string) (*CronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+	if descriptor, exists := cronDescriptors[expression]; exists {
+		expression = descriptor
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 { // nolint:gomnd // reason: five cron fields
+		return nil, fmt.Errorf("%w: %q: expected 5 fields, found %d", ErrInvalidCron, expression, len(fields))
+	}
+
+	schedule := &CronSchedule{
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}
+
+	var err error
+	for index, parse := range []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{field: cronMinute, bits: &schedule.minutes},
+		{field: cronHour, bits: &schedule.hours},
+		{field: cronDay, bits: &schedule.days},
+		{field: cronMonth, bits: &schedule.months},
+		{field: cronWeekday, bits: &schedule.weekdays},
+	} {
+		if *parse.bits, err = parse.field.parse(fields[index]); err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidCron, expression, err)
+		}
+	}
+
+	// Sunday is both 0 and 7.
+	if schedule.weekdays&(1<<7) != 0 {
+		schedule.weekdays |= 1
+	}
+
+	return schedule, nil
+}
+
+// Next returns the first time after t matching the schedule, in the location
+// of t. Returns the zero time if nothing matches within five years.
+func (self *CronSchedule) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := next.AddDate(cronSearchYears, 0, 0)
+
+	for next.Before(limit) {
+		switch {
+		case !has(self.months, int(next.Month())):
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !self.matchesDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case !has(self.hours, next.Hour()):
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+		case !has(self.minutes, next.Minute()):
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+func (self *CronSchedule) matchesDay(t time.Time) bool {
+	day := has(self.days, t.Day())
+	weekday := has(self.weekdays, int(t.Weekday()))
+
+	if self.anyDay || self.anyWeekday {
+		return day && weekday
+	}
+
+	return day || weekday
+}
+
+func has(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
+
+// parse a field into a bit set of its values.
+func (self cronField) parse(field string) (uint64, error) {
+	var set uint64
+
+	for _, part := range strings.Split(field, ",") {
+		valueRange, stepText, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step < 1 {
+				return 0, fmt.Errorf("%s: invalid step %q", self.name, stepText)
+			}
+		}
+
+		low, high := self.min, self.max
+		switch {
+		case valueRange == "*":
+		case strings.Contains(valueRange, "-"):
+			lowText, highText, _ := strings.Cut(valueRange, "-")
+			var err error
+			if low, err = self.value(lowText); err != nil {
+				return 0, err
+			}
+			if high, err = self.value(highText); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("%s: invalid range %q", self.name, valueRange)
+			}
+		default:
+			var err error
+			if low, err = self.value(valueRange); err != nil {
+				return 0, err
+			}
+			if !hasStep {
+				high = low
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+
+	if set == 0 {
+		return 0, fmt.Errorf("%s: no values in %q", self.name, field)
+	}
+
+	return set, nil
+}
+
+func (self cronField) value(text string) (int, error) {
+	value, err := strconv.Atoi(text)
+	if err != nil || value < self.min || value > self.max {
+		return 0, fmt.Errorf("%s: %q is not between %d and %d", self.name, text, self.min, self.max)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,62 @@
+package gofunc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context/gofunc"
+)
+
+func Test_ParseCron_Next(t *testing.T) {
+	t.Parallel()
+
+	// Saturday.
+	from := time.Date(2026, 10, 17, 10, 7, 30, 0, time.UTC)
+
+	for _, test := range []struct {
+		expression string
+		expected   time.Time
+	}{
+		{expression: "* * * * *", expected: time.Date(2026, 10, 17, 10, 8, 0, 0, time.UTC)},
+		{expression: "*/20 * * * *", expected: time.Date(2026, 10, 17, 10, 20, 0, 0, time.UTC)},
+		{expression: "5,50 10 * * *", expected: time.Date(2026, 10, 17, 10, 50, 0, 0, time.UTC)},
+		{expression: "0 9 * * 1-5", expected: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{expression: "0 0 * * 7", expected: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{expression: "30 8 1 * *", expected: time.Date(2026, 11, 1, 8, 30, 0, 0, time.UTC)},
+		// Either the 13th or a Friday.
+		{expression: "0 0 13 * 5", expected: time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 12 29 2 *", expected: time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{expression: "0 0-12/6 * * *", expected: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)},
+		{expression: "@hourly", expected: time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC)},
+		{expression: "@monthly", expected: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 30 2 *", expected: time.Time{}},
+	} {
+		schedule, err := gofunc.ParseCron(test.expression)
+		if assert.NoError(t, err, test.expression) {
+			assert.Equal(t, test.expected, schedule.Next(from), test.expression)
+		}
+	}
+}
+
+func Test_ParseCron_invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, expression := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@never",
+	} {
+		_, err := gofunc.ParseCron(expression)
+		assert.ErrorIs(t, err, gofunc.ErrInvalidCron, expression)
+	}
+}
@@ -0,0 +1,102 @@
+package gofunc
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/wspowell/context"
+)
+
+// PanicError is the error returned when a goroutine started by Run panics.
+// It satisfies errors.Is(err, errors.ErrPanic).
+type PanicError struct {
+	err error
+}
+
+func newPanicError(err error) *PanicError {
+	return &PanicError{
+		err: err,
+	}
+}
+
+func (self *PanicError) Error() string {
+	return self.err.Error()
+}
+
+func (self *PanicError) InternalCode() string {
+	return context.CodeGofuncPanic
+}
+
+func (self *PanicError) Unwrap() error {
+	return self.err
+}
+
+// Error annotates an error with the Context name and goroutine that produced it.
+type Error struct {
+	err       error
+	name      string
+	goroutine uint64
+}
+
+// Annotate err with the name and goroutine of ctx.
+// Returns nil if err is nil.
+func Annotate(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{
+		err:       err,
+		name:      context.NamePath(ctx),
+		goroutine: context.Goroutine(ctx),
+	}
+}
+
+// Annotated wraps fn so that any error it returns is annotated using Annotate.
+func Annotated(fn RunFn) RunFn {
+	return func(ctx context.Context) error {
+		return Annotate(ctx, fn(ctx))
+	}
+}
+
+// Name returns the NamePath of the Context that produced the error.
+func (self *Error) Name() string {
+	return self.name
+}
+
+// Goroutine returns the ID of the goroutine that produced the error.
+// Always zero in release builds.
+func (self *Error) Goroutine() uint64 {
+	return self.goroutine
+}
+
+func (self *Error) Error() string {
+	return self.prefix() + self.err.Error()
+}
+
+func (self *Error) Unwrap() error {
+	return self.err
+}
+
+func (self *Error) Format(state fmt.State, verb rune) {
+	fmt.Fprint(state, self.prefix())
+
+	// nolint:errorlint // reason: type conversion, not an error check.
+	if formatter, ok := self.err.(fmt.Formatter); ok {
+		formatter.Format(state, verb)
+	} else {
+		fmt.Fprint(state, self.err.Error())
+	}
+}
+
+func (self *Error) prefix() string {
+	prefix := "gofunc"
+	if self.name != "" {
+		prefix += " " + self.name
+	}
+	if self.goroutine != 0 {
+		prefix += " (goroutine " + strconv.FormatUint(self.goroutine, 10) + ")"
+	}
+
+	return prefix + ": "
+}
@@ -0,0 +1,31 @@
+//go:build !release
+// +build !release
+
+package gofunc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+func Test_Annotate_goroutine(t *testing.T) {
+	t.Parallel()
+
+	var goroutine uint64
+	err := <-gofunc.Run(context.Background(), func(ctx context.Context) error {
+		goroutine = context.Goroutine(ctx)
+
+		return gofunc.Annotate(ctx, errTest)
+	})
+
+	var annotated *gofunc.Error
+	assert.True(t, errors.As(err, &annotated))
+	assert.NotZero(t, goroutine)
+	assert.Equal(t, goroutine, annotated.Goroutine())
+	assert.True(t, errors.Is(err, errTest))
+}
@@ -0,0 +1,58 @@
+package gofunc_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+func Test_Run_panic(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithName(context.Background(), "worker")
+	err := <-gofunc.Run(ctx, func(ctx context.Context) error {
+		panic("boom")
+	})
+
+	assert.True(t, errors.Is(err, errors.ErrPanic))
+	assert.Equal(t, "CTX3", context.InternalCode(err))
+
+	var panicErr *gofunc.PanicError
+	assert.True(t, errors.As(err, &panicErr))
+
+	var annotated *gofunc.Error
+	assert.True(t, errors.As(err, &annotated))
+	assert.Equal(t, "worker", annotated.Name())
+	assert.Contains(t, err.Error(), "gofunc worker")
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func Test_Annotated(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithName(context.Background(), "api")
+	ctx, cancel := context.WithCancel(context.WithName(ctx, "checkout"))
+	cancel()
+
+	err := <-gofunc.Run(ctx, gofunc.Annotated(func(ctx context.Context) error {
+		return ctx.Err()
+	}))
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, "CTX0", context.InternalCode(err))
+
+	var annotated *gofunc.Error
+	assert.True(t, errors.As(err, &annotated))
+	assert.Equal(t, "api/checkout", annotated.Name())
+	assert.Contains(t, fmt.Sprintf("%v", err), "gofunc api/checkout")
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	assert.Nil(t, <-gofunc.Run(ctx, gofunc.Annotated(func(ctx context.Context) error {
+		return nil
+	})))
+}
@@ -0,0 +1,66 @@
+package gofunc
+
+import (
+	gocontext "context" // nolint:depguard // reason: runtime/trace associates tasks through the stdlib context
+	"runtime/trace"
+	"sync/atomic"
+
+	"github.com/wspowell/context"
+)
+
+// RegionType is the runtime/trace region type of goroutines started by Run.
+const RegionType = "gofunc"
+
+// nolint:gochecknoglobals // reason: process wide switch like context.EnableTracking
+var executionTracing int32
+
+// EnableExecutionTracing makes Run create a runtime/trace task for every
+// goroutine it starts while an execution trace is being recorded, and run the
+// goroutine within a region of that task. Tasks are named after the Context,
+// see context.NamePath, and nest as the goroutines do.
+//
+// Execution tracing is process wide. Enable it during initialization, before
+// the first call to Run, since goroutines already running have no task.
+func EnableExecutionTracing() {
+	atomic.StoreInt32(&executionTracing, 1)
+}
+
+// DisableExecutionTracing stops Run from creating runtime/trace tasks.
+func DisableExecutionTracing() {
+	atomic.StoreInt32(&executionTracing, 0)
+}
+
+type taskKey struct{}
+
+// startTask starts a runtime/trace task for the goroutine of ctx if execution
+// tracing is enabled. The returned function ends the task, or is nil if no
+// task was started.
+func startTask(ctx context.Context) (context.Context, func()) {
+	if atomic.LoadInt32(&executionTracing) == 0 || !trace.IsEnabled() {
+		return ctx, nil
+	}
+
+	name := context.NamePath(ctx)
+	if name == "" {
+		name = RegionType
+	}
+
+	taskCtx, task := trace.NewTask(traceContext(ctx), name)
+
+	return context.WithValue(ctx, taskKey{}, taskCtx), task.End
+}
+
+// Region runs fn within a runtime/trace region of the task of the goroutine
+// ctx is localized to, see EnableExecutionTracing.
+func Region(ctx context.Context, regionType string, fn func()) {
+	trace.WithRegion(traceContext(ctx), regionType, fn)
+}
+
+// traceContext returns the stdlib Context that carries the runtime/trace task of ctx.
+func traceContext(ctx context.Context) gocontext.Context {
+	if taskCtx, ok := ctx.Value(taskKey{}).(gocontext.Context); ok {
+		return taskCtx
+	}
+
+	return ctx
+}
@@ -0,0 +1,37 @@
+package gofunc_test
+
+import (
+	"bytes"
+	"runtime/trace"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+// Not parallel: execution tracing is process wide.
+func Test_EnableExecutionTracing(t *testing.T) {
+	if trace.IsEnabled() {
+		t.Skip("execution trace already recording")
+	}
+
+	gofunc.EnableExecutionTracing()
+	t.Cleanup(gofunc.DisableExecutionTracing)
+
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, trace.Start(buffer))
+
+	ctx := context.WithName(context.Background(), "exectrace-task")
+	err := <-gofunc.Run(ctx, func(ctx context.Context) error {
+		gofunc.Region(ctx, "exectrace-region", func() {})
+
+		return nil
+	})
+	trace.Stop()
+
+	assert.NoError(t, err)
+	assert.Contains(t, buffer.String(), "exectrace-task")
+	assert.Contains(t, buffer.String(), "exectrace-region")
+}
@@ -0,0 +1,213 @@
+package gofunc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+)
+
+// HedgeStats counts the outcomes of Hedge calls.
+type HedgeStats struct {
+	// Calls to Hedge.
+	Calls int64
+	// Attempts started, including the first attempt of each call.
+	Attempts int64
+	// Hedged calls started an attempt because the previous attempts had not
+	// succeeded within the delay.
+	Hedged int64
+	// Retries are attempts started because an attempt failed. They are not hedges.
+	Retries int64
+	// Wins are calls that succeeded with an attempt started as a hedge.
+	Wins int64
+	// Suppressed hedges were not started because the deadline was too close.
+	Suppressed int64
+}
+
+// HedgeCounter accumulates the HedgeStats of the Hedge calls it is passed to,
+// see WithHedgeCounter. It is safe for concurrent use.
+type HedgeCounter struct {
+	mutex sync.Mutex
+	stats HedgeStats
+}
+
+// Stats returns the statistics accumulated so far.
+func (self *HedgeCounter) Stats() HedgeStats {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return self.stats
+}
+
+func (self *HedgeCounter) record(update func(stats *HedgeStats)) {
+	if self == nil {
+		return
+	}
+
+	self.mutex.Lock()
+	update(&self.stats)
+	self.mutex.Unlock()
+}
+
+// HedgeOption configures Hedge.
+type HedgeOption func(*hedgeConfig)
+
+type hedgeConfig struct {
+	counter *HedgeCounter
+}
+
+// WithHedgeCounter accumulates the outcome of the call into counter.
+// Share a counter between the calls that should be reported together, for
+// example one per downstream dependency.
+func WithHedgeCounter(counter *HedgeCounter) HedgeOption {
+	return func(config *hedgeConfig) {
+		config.counter = counter
+	}
+}
+
+type hedgeResult[T any] struct {
+	hedge bool
+	value T
+	err   error
+}
+
+// Hedge calls fn and, if it has not succeeded within delay, calls it again
+// concurrently, up to maxAttempts attempts in total. A failed attempt starts
+// the next attempt right away as a retry. Every attempt runs using Run on its
+// own cancelable Context.
+//
+// The result of the first successful attempt is returned and the other
+// attempts are canceled without waiting for them. If every attempt fails, the
+// error of the last attempt to fail is returned. If ctx is done first, its
+// error is returned.
+//
+// The first attempt always starts. Hedges and retries are not started while
+// the remaining deadline of ctx is shorter than delay. See WithHedgeCounter
+// for how often hedging won.
+func Hedge[T any](ctx context.Context, delay time.Duration, maxAttempts int, fn func(ctx context.Context) (T, error), options ...HedgeOption) (T, error) {
+	var zero T
+
+	config := hedgeConfig{}
+	for _, option := range options {
+		option(&config)
+	}
+	counter := config.counter
+
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	counter.record(func(stats *HedgeStats) {
+		stats.Calls++
+	})
+
+	results := make(chan hedgeResult[T], maxAttempts)
+	cancels := make([]context.CancelFunc, 0, maxAttempts)
+	hedged := false
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	start := func(hedge bool) {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+
+		// The result is sent from the goroutine of the attempt, panics included.
+		Run(attemptCtx, func(ctx context.Context) error {
+			result := hedgeResult[T]{
+				hedge: hedge,
+			}
+			if panicErr := errors.Catch(func() {
+				result.value, result.err = fn(ctx)
+			}); panicErr != nil {
+				result.err = Annotate(ctx, newPanicError(panicErr))
+			}
+			results <- result
+
+			return result.err
+		})
+
+		firstHedge := hedge && !hedged
+		hedged = hedged || hedge
+		retry := !hedge && len(cancels) > 1
+		counter.record(func(stats *HedgeStats) {
+			stats.Attempts++
+			if firstHedge {
+				stats.Hedged++
+			}
+			if retry {
+				stats.Retries++
+			}
+		})
+	}
+
+	// next starts another attempt if allowed.
+	next := func(hedge bool) bool {
+		if len(cancels) == maxAttempts {
+			return false
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			counter.record(func(stats *HedgeStats) {
+				stats.Suppressed++
+			})
+
+			return false
+		}
+
+		start(hedge)
+
+		return true
+	}
+
+	start(false)
+	pending := 1
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err() // nolint:wrapcheck // reason: context errors returned as is
+		case result := <-results:
+			pending--
+
+			if result.err == nil {
+				if result.hedge {
+					counter.record(func(stats *HedgeStats) {
+						stats.Wins++
+					})
+				}
+
+				return result.value, nil
+			}
+
+			if next(false) {
+				pending++
+				resetTimer(timer, delay)
+			} else if pending == 0 {
+				return zero, result.err
+			}
+		case <-timer.C:
+			if next(true) {
+				pending++
+				timer.Reset(delay)
+			}
+		}
+	}
+}
+
+// resetTimer resets a timer that may be running or expired.
+func resetTimer(timer *time.Timer, delay time.Duration) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+	timer.Reset(delay)
+}
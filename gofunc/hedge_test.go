@@ -0,0 +1,120 @@
+package gofunc_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+func Test_Hedge_first_wins(t *testing.T) {
+	t.Parallel()
+
+	counter := &gofunc.HedgeCounter{}
+
+	value, err := gofunc.Hedge(context.Background(), time.Second, 3, func(ctx context.Context) (string, error) {
+		return "first", nil
+	}, gofunc.WithHedgeCounter(counter))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "first", value)
+	assert.Equal(t, gofunc.HedgeStats{Calls: 1, Attempts: 1}, counter.Stats())
+}
+
+func Test_Hedge_hedge_wins(t *testing.T) {
+	t.Parallel()
+
+	counter := &gofunc.HedgeCounter{}
+
+	var (
+		attempts int32
+		canceled = make(chan struct{})
+	)
+	value, err := gofunc.Hedge(context.Background(), 5*time.Millisecond, 2, func(ctx context.Context) (int32, error) {
+		attempt := atomic.AddInt32(&attempts, 1)
+		if attempt == 1 {
+			// Slow attempt, canceled once the hedge wins.
+			<-ctx.Done()
+			close(canceled)
+
+			return 0, ctx.Err()
+		}
+
+		return attempt, nil
+	}, gofunc.WithHedgeCounter(counter))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), value)
+	<-canceled
+	assert.Equal(t, gofunc.HedgeStats{Calls: 1, Attempts: 2, Hedged: 1, Wins: 1}, counter.Stats())
+}
+
+func Test_Hedge_failures(t *testing.T) {
+	t.Parallel()
+
+	counter := &gofunc.HedgeCounter{}
+
+	var attempts int32
+	_, err := gofunc.Hedge(context.Background(), time.Second, 3, func(ctx context.Context) (int, error) {
+		if atomic.AddInt32(&attempts, 1) == 2 {
+			panic("boom")
+		}
+
+		return 0, errTest
+	}, gofunc.WithHedgeCounter(counter))
+
+	// Failed attempts start the next attempt right away, as retries rather than hedges.
+	assert.ErrorIs(t, err, errTest)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	assert.Equal(t, gofunc.HedgeStats{Calls: 1, Attempts: 3, Retries: 2}, counter.Stats())
+
+	// A retry that succeeds is not a win of hedging.
+	atomic.StoreInt32(&attempts, 0)
+	value, err := gofunc.Hedge(context.Background(), time.Second, 3, func(ctx context.Context) (int32, error) {
+		if attempt := atomic.AddInt32(&attempts, 1); attempt != 1 {
+			return attempt, nil
+		}
+
+		return 0, errTest
+	}, gofunc.WithHedgeCounter(counter))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), value)
+	assert.Equal(t, gofunc.HedgeStats{Calls: 2, Attempts: 5, Retries: 3}, counter.Stats())
+}
+
+func Test_Hedge_panic(t *testing.T) {
+	t.Parallel()
+
+	_, err := gofunc.Hedge(context.Background(), time.Second, 1, func(ctx context.Context) (int, error) {
+		panic("boom")
+	})
+
+	assert.ErrorIs(t, err, errors.ErrPanic)
+}
+
+func Test_Hedge_deadline(t *testing.T) {
+	t.Parallel()
+
+	counter := &gofunc.HedgeCounter{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	var attempts int32
+	_, err := gofunc.Hedge(ctx, 20*time.Millisecond, 3, func(ctx context.Context) (int, error) {
+		atomic.AddInt32(&attempts, 1)
+		<-ctx.Done()
+
+		return 0, ctx.Err()
+	}, gofunc.WithHedgeCounter(counter))
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	assert.Equal(t, gofunc.HedgeStats{Calls: 1, Attempts: 1, Suppressed: 1}, counter.Stats())
+}
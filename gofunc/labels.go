@@ -0,0 +1,121 @@
+package gofunc
+
+import (
+	"fmt"
+	"runtime/pprof"
+	"sort"
+	"sync"
+
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+)
+
+// ContextLabel is the pprof label holding the name path of the Context, see context.NamePath.
+const ContextLabel = "context"
+
+// ErrDuplicateLabel is returned when registering a label name twice.
+var ErrDuplicateLabel = errors.New("label already registered")
+
+// LabelFunc returns the value of a pprof label for ctx, or "" to omit the label.
+type LabelFunc func(ctx context.Context) string
+
+type label struct {
+	name string
+	fn   LabelFunc
+}
+
+// nolint:gochecknoglobals // reason: registry of pprof labels
+var (
+	labelsMutex sync.RWMutex
+	labels      = []label{
+		{
+			name: ContextLabel,
+			fn:   context.NamePath,
+		},
+	}
+)
+
+// RegisterLabel registers a runtime/pprof label applied to every goroutine
+// started by Run, so that CPU profiles can be sliced by Context.
+// ContextLabel is registered by default.
+//
+// Labels are process wide. Register them during initialization, before the
+// first call to Run, since goroutines already running keep their labels.
+func RegisterLabel(name string, fn LabelFunc) error {
+	labelsMutex.Lock()
+	defer labelsMutex.Unlock()
+
+	for _, registered := range labels {
+		if registered.name == name {
+			return fmt.Errorf("%w: %s", ErrDuplicateLabel, name)
+		}
+	}
+
+	// Copy on write, Labels reads the registered labels outside the lock.
+	registered := append(labels[:len(labels):len(labels)], label{
+		name: name,
+		fn:   fn,
+	})
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].name < registered[j].name
+	})
+	labels = registered
+
+	return nil
+}
+
+// RegisterLabelValue registers a runtime/pprof label holding the immutable
+// value of key, formatted using fmt.Sprint.
+//
+// 	gofunc.RegisterLabelValue("tenant", tenantKey{})
+func RegisterLabelValue[K comparable](name string, key K) error {
+	return RegisterLabel(name, func(ctx context.Context) string {
+		value := context.ImmutableValue(ctx, key)
+		if value == nil {
+			return ""
+		}
+
+		return fmt.Sprint(value)
+	})
+}
+
+// UnregisterLabel removes the label registered under name.
+// Returns false if name is not registered.
+func UnregisterLabel(name string) bool {
+	labelsMutex.Lock()
+	defer labelsMutex.Unlock()
+
+	for index, registered := range labels {
+		if registered.name == name {
+			labels = append(labels[:index:index], labels[index+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Labels returns the registered labels of ctx as name-value pairs, sorted by
+// name. Labels with empty values are omitted.
+func Labels(ctx context.Context) []string {
+	labelsMutex.RLock()
+	registered := labels
+	labelsMutex.RUnlock()
+
+	pairs := make([]string, 0, 2*len(registered))
+	for _, registered := range registered {
+		if value := registered.fn(ctx); value != "" {
+			pairs = append(pairs, registered.name, value)
+		}
+	}
+
+	return pairs
+}
+
+// setGoroutineLabels replaces the pprof labels the goroutine inherited from
+// its creator with the labels of ctx.
+func setGoroutineLabels(ctx context.Context) {
+	pprof.SetGoroutineLabels(pprof.WithLabels(ctx, pprof.Labels(Labels(ctx)...)))
+}
@@ -0,0 +1,40 @@
+package gofunc_test
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+type tenantKey struct{}
+
+// Not parallel: labels are process wide.
+func Test_Labels(t *testing.T) {
+	assert.NoError(t, gofunc.RegisterLabelValue("tenant", tenantKey{}))
+	t.Cleanup(func() { gofunc.UnregisterLabel("tenant") })
+
+	assert.ErrorIs(t, gofunc.RegisterLabel("tenant", context.NamePath), gofunc.ErrDuplicateLabel)
+
+	ctx := context.WithName(context.Background(), "api")
+	ctx = context.WithName(ctx, "labels")
+	assert.Equal(t, []string{"context", "api/labels"}, gofunc.Labels(ctx))
+
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+	assert.Equal(t, []string{"context", "api/labels", "tenant", "acme"}, gofunc.Labels(ctx))
+
+	profile := &bytes.Buffer{}
+	err := <-gofunc.Run(ctx, func(ctx context.Context) error {
+		return pprof.Lookup("goroutine").WriteTo(profile, 1)
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, profile.String(), `# labels: {"context":"api/labels", "tenant":"acme"}`)
+
+	assert.True(t, gofunc.UnregisterLabel("tenant"))
+	assert.False(t, gofunc.UnregisterLabel("tenant"))
+	assert.Equal(t, []string{"context", "api/labels"}, gofunc.Labels(ctx))
+}
@@ -0,0 +1,164 @@
+package gofunc
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/wspowell/context"
+)
+
+// ItemError is the error of one item of Map or ForEach.
+type ItemError struct {
+	index int
+	err   error
+}
+
+// Index of the item that failed.
+func (self *ItemError) Index() int {
+	return self.index
+}
+
+func (self *ItemError) Error() string {
+	return "item " + strconv.Itoa(self.index) + ": " + self.err.Error()
+}
+
+func (self *ItemError) Unwrap() error {
+	return self.err
+}
+
+// ItemErrors are the errors of every failed item of Map or ForEach, in input
+// order, when using CollectErrors.
+type ItemErrors []*ItemError
+
+func (self ItemErrors) Error() string {
+	messages := make([]string, len(self))
+	for index, err := range self {
+		messages[index] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (self ItemErrors) Unwrap() []error {
+	errs := make([]error, len(self))
+	for index, err := range self {
+		errs[index] = err
+	}
+
+	return errs
+}
+
+// MapOption configures Map and ForEach.
+type MapOption func(*mapConfig)
+
+type mapConfig struct {
+	collectErrors bool
+}
+
+// CollectErrors runs every item even if some fail, and returns the errors of
+// all failed items as ItemErrors. By default, the first failure cancels the
+// remaining items.
+func CollectErrors() MapOption {
+	return func(config *mapConfig) {
+		config.collectErrors = true
+	}
+}
+
+// Map calls fn for every item, running at most limit items concurrently, and
+// returns the results in input order. Each item runs using Run, on its own
+// localized Context, so a panic fails only its item. A limit below one runs
+// every item concurrently.
+//
+// By default, the first item to fail cancels the Context of the other items,
+// no further items are started, and its *ItemError is returned. See
+// CollectErrors to run every item instead. If ctx is done before every item
+// was started and no item failed, ctx.Err() is returned. The results of items
+// that failed or did not run are zero.
+func Map[In any, Out any](ctx context.Context, items []In, limit int, fn func(ctx context.Context, item In) (Out, error), options ...MapOption) ([]Out, error) {
+	var config mapConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	if limit < 1 || limit > len(items) {
+		limit = len(items)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var firstFailed atomic.Int64
+	firstFailed.Store(-1)
+
+	outputs := make([]Out, len(items))
+	results := make([]<-chan error, 0, len(items))
+	tokens := make(chan struct{}, limit)
+
+	for index, item := range items {
+		select {
+		case tokens <- struct{}{}:
+		case <-runCtx.Done():
+		}
+		if runCtx.Err() != nil {
+			break
+		}
+
+		results = append(results, Run(runCtx, func(ctx context.Context) error {
+			defer func() {
+				<-tokens
+			}()
+
+			succeeded := false
+			defer func() {
+				// Also runs when fn panics.
+				if !succeeded && firstFailed.CompareAndSwap(-1, int64(index)) && !config.collectErrors {
+					cancel()
+				}
+			}()
+
+			output, err := fn(ctx, item)
+			outputs[index] = output
+			succeeded = err == nil
+
+			return err
+		}))
+	}
+
+	var errs ItemErrors
+	for index, result := range results {
+		if err := <-result; err != nil {
+			errs = append(errs, &ItemError{index: index, err: err})
+		}
+	}
+
+	switch {
+	case len(errs) == 0:
+		if len(results) != len(items) {
+			return outputs, ctx.Err()
+		}
+
+		return outputs, nil
+	case config.collectErrors:
+		return outputs, errs
+	default:
+		first := int(firstFailed.Load())
+		for _, err := range errs {
+			if err.index == first {
+				return outputs, err
+			}
+		}
+
+		return outputs, errs[0]
+	}
+}
+
+// ForEach calls fn for every item, running at most limit items concurrently.
+// See Map.
+func ForEach[In any](ctx context.Context, items []In, limit int, fn func(ctx context.Context, item In) error, options ...MapOption) error {
+	_, err := Map(ctx, items, limit, func(ctx context.Context, item In) (struct{}, error) {
+		return struct{}{}, fn(ctx, item)
+	}, options...)
+
+	return err
+}
@@ -0,0 +1,136 @@
+package gofunc_test
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+type itemKey struct{}
+
+func Test_Map(t *testing.T) {
+	t.Parallel()
+
+	var running, maxRunning atomic.Int32
+
+	items := []int{5, 4, 3, 2, 1, 0}
+	results, err := gofunc.Map(context.Background(), items, 2, func(ctx context.Context, item int) (string, error) {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			observed := maxRunning.Load()
+			if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+
+		// Each item has its own local values.
+		assert.Nil(t, ctx.Value(itemKey{}))
+		context.WithLocalValue(ctx, itemKey{}, item)
+
+		time.Sleep(time.Duration(item) * time.Millisecond)
+
+		return strconv.Itoa(item), nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"5", "4", "3", "2", "1", "0"}, results)
+	assert.Equal(t, int32(2), maxRunning.Load())
+}
+
+func Test_Map_first_error(t *testing.T) {
+	t.Parallel()
+
+	var started atomic.Int32
+
+	items := make([]int, 100)
+	for index := range items {
+		items[index] = index
+	}
+
+	_, err := gofunc.Map(context.Background(), items, 2, func(ctx context.Context, item int) (int, error) {
+		started.Add(1)
+		if item == 3 {
+			return 0, errTest
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+
+		return item, nil
+	})
+
+	var itemErr *gofunc.ItemError
+	assert.True(t, errors.As(err, &itemErr))
+	assert.Equal(t, 3, itemErr.Index())
+	assert.ErrorIs(t, err, errTest)
+	assert.Less(t, started.Load(), int32(100))
+}
+
+func Test_Map_CollectErrors(t *testing.T) {
+	t.Parallel()
+
+	results, err := gofunc.Map(context.Background(), []int{0, 1, 2, 3}, 0, func(ctx context.Context, item int) (int, error) {
+		switch item {
+		case 1:
+			return 0, errTest
+		case 3:
+			panic("boom")
+		}
+
+		return item * 10, nil
+	}, gofunc.CollectErrors())
+
+	assert.Equal(t, []int{0, 0, 20, 0}, results)
+
+	var itemErrs gofunc.ItemErrors
+	assert.True(t, errors.As(err, &itemErrs))
+	assert.Len(t, itemErrs, 2)
+	assert.Equal(t, 1, itemErrs[0].Index())
+	assert.Equal(t, 3, itemErrs[1].Index())
+	assert.ErrorIs(t, err, errTest)
+	assert.ErrorIs(t, err, errors.ErrPanic)
+}
+
+func Test_ForEach(t *testing.T) {
+	t.Parallel()
+
+	var sum atomic.Int64
+	err := gofunc.ForEach(context.Background(), []int64{1, 2, 3}, 2, func(ctx context.Context, item int64) error {
+		sum.Add(item)
+
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), sum.Load())
+
+	err = gofunc.ForEach(context.Background(), []int{1}, 1, func(ctx context.Context, item int) error {
+		panic("boom")
+	})
+	assert.ErrorIs(t, err, errors.ErrPanic)
+}
+
+func Test_ForEach_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var ran atomic.Int32
+	err := gofunc.ForEach(ctx, []int{1, 2, 3, 4}, 1, func(ctx context.Context, item int) error {
+		ran.Add(1)
+		cancel()
+
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, ran.Load(), int32(4))
+}
@@ -8,17 +8,48 @@ import (
 
 type RunFn func(ctx context.Context) error
 
+// Run fn in a new goroutine on a Context localized to that goroutine.
+// The returned channel receives exactly one value: the error returned by fn,
+// or a *PanicError annotated with the Context name and goroutine if fn panics.
+// The local values of the goroutine are joined once fn returns, see context.Join.
+//
+// The goroutine is labeled for runtime/pprof using the registered labels, see
+// RegisterLabel, and traced as a runtime/trace task if enabled, see
+// EnableExecutionTracing. The goroutine is listed by Running until fn returns.
 func Run(ctx context.Context, fn RunFn) <-chan error {
-	err := make(chan error)
+	result := make(chan error, 1)
+
+	id := startGoroutine(ctx)
 
 	go func(ctx context.Context) {
-		err <- errors.Catch(func() {
+		var err error
+		if panicErr := errors.Catch(func() {
+			var endTask func()
+			if ctx, endTask = startTask(ctx); endTask != nil {
+				defer endTask()
+			}
+
 			ctx = context.Localize(ctx)
-			err <- fn(ctx)
-		})
+			defer context.Join(ctx)
+
+			setGoroutineLabels(ctx)
+
+			if endTask != nil {
+				Region(ctx, RegionType, func() {
+					err = fn(ctx)
+				})
+			} else {
+				err = fn(ctx)
+			}
+		}); panicErr != nil {
+			err = Annotate(ctx, newPanicError(panicErr))
+		}
+
+		exitGoroutine(id)
+		result <- err
 	}(ctx)
 
-	return err
+	return result
 }
 
 type Runnable interface {
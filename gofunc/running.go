@@ -0,0 +1,135 @@
+package gofunc
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wspowell/context"
+)
+
+// Goroutine describes a goroutine started by Run that has not returned yet.
+type Goroutine struct {
+	// ID is unique among the goroutines started by Run.
+	ID uint64
+	// Name is the NamePath of the Context the goroutine was started on.
+	Name    string
+	Started time.Time
+
+	ctx context.Context
+}
+
+// Context the goroutine was started on, before it was localized.
+// Use context.ImmutableValue to read its values from another goroutine.
+func (self Goroutine) Context() context.Context {
+	return self.ctx
+}
+
+// runningShards spreads the registry over several locks so that goroutines
+// starting and exiting concurrently rarely contend.
+const runningShards = 64
+
+type runningShard struct {
+	mutex      sync.Mutex
+	goroutines map[uint64]Goroutine
+}
+
+// nolint:gochecknoglobals // reason: registry of running goroutines
+var (
+	running [runningShards]runningShard
+	nextID  uint64
+	// draining counts the calls to Drain waiting for goroutines to exit.
+	// Exits only signal exited while it is non-zero.
+	draining int32
+	// exited is closed and replaced whenever a goroutine returns while draining.
+	exitedMutex sync.Mutex
+	exited      = make(chan struct{})
+)
+
+func startGoroutine(ctx context.Context) uint64 {
+	id := atomic.AddUint64(&nextID, 1)
+
+	shard := &running[id%runningShards]
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	if shard.goroutines == nil {
+		shard.goroutines = map[uint64]Goroutine{}
+	}
+	shard.goroutines[id] = Goroutine{
+		ID:      id,
+		Name:    context.NamePath(ctx),
+		Started: time.Now(),
+		ctx:     ctx,
+	}
+
+	return id
+}
+
+func exitGoroutine(id uint64) {
+	shard := &running[id%runningShards]
+	shard.mutex.Lock()
+	delete(shard.goroutines, id)
+	shard.mutex.Unlock()
+
+	if atomic.LoadInt32(&draining) != 0 {
+		exitedMutex.Lock()
+		close(exited)
+		exited = make(chan struct{})
+		exitedMutex.Unlock()
+	}
+}
+
+// Running returns the goroutines started by Run that have not returned yet,
+// oldest first.
+func Running() []Goroutine {
+	return runningMatching(nil)
+}
+
+func runningMatching(filter func(goroutine Goroutine) bool) []Goroutine {
+	var goroutines []Goroutine
+	for index := range running {
+		shard := &running[index]
+
+		shard.mutex.Lock()
+		for _, goroutine := range shard.goroutines {
+			if filter == nil || filter(goroutine) {
+				goroutines = append(goroutines, goroutine)
+			}
+		}
+		shard.mutex.Unlock()
+	}
+
+	sort.Slice(goroutines, func(i, j int) bool {
+		return goroutines[i].ID < goroutines[j].ID
+	})
+
+	return goroutines
+}
+
+// Drain waits until every running goroutine matching filter has returned, or
+// until ctx is done. Returns the matching goroutines still running, if any.
+// A nil filter matches every goroutine.
+func Drain(ctx context.Context, filter func(goroutine Goroutine) bool) []Goroutine {
+	atomic.AddInt32(&draining, 1)
+	defer atomic.AddInt32(&draining, -1)
+
+	for {
+		// Observe exited before the registry so that no exit is missed.
+		exitedMutex.Lock()
+		changed := exited
+		exitedMutex.Unlock()
+
+		remaining := runningMatching(filter)
+		if len(remaining) == 0 {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return remaining
+		}
+	}
+}
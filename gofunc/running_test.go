@@ -0,0 +1,67 @@
+package gofunc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+type drainKey struct{}
+
+func Test_Drain(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithName(context.Background(), "drain")
+	ctx = context.WithValue(ctx, drainKey{}, true)
+
+	isDrained := func(goroutine gofunc.Goroutine) bool {
+		return context.ImmutableValue(goroutine.Context(), drainKey{}) == true
+	}
+
+	release := make(chan struct{})
+	fast := gofunc.Run(ctx, func(ctx context.Context) error {
+		<-release
+
+		return nil
+	})
+	slowCtx, cancelSlow := context.WithCancel(context.WithName(ctx, "slow"))
+	slow := gofunc.Run(slowCtx, func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	})
+
+	running := gofunc.Drain(context.Background(), func(goroutine gofunc.Goroutine) bool {
+		return false
+	})
+	assert.Empty(t, running)
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	running = gofunc.Drain(drainCtx, isDrained)
+	assert.Len(t, running, 2)
+	assert.Equal(t, "drain", running[0].Name)
+	assert.Equal(t, "drain/slow", running[1].Name)
+	assert.Less(t, running[0].ID, running[1].ID)
+
+	close(release)
+	assert.NoError(t, <-fast)
+
+	drainCtx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	running = gofunc.Drain(drainCtx, isDrained)
+	assert.Len(t, running, 1)
+	assert.Equal(t, "drain/slow", running[0].Name)
+	assert.Contains(t, gofunc.Running(), running[0])
+
+	cancelSlow()
+	assert.NoError(t, <-slow)
+
+	assert.Empty(t, gofunc.Drain(context.Background(), isDrained))
+}
@@ -0,0 +1,222 @@
+package gofunc
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/wspowell/context"
+)
+
+// ScheduleOption configures Every, After, and Cron.
+type ScheduleOption func(*scheduleConfig)
+
+type scheduleConfig struct {
+	clock           Clock
+	jitter          time.Duration
+	skipOverlapping bool
+	catchUp         int
+	errorHandler    func(err error)
+}
+
+// WithClock uses clock instead of the system clock.
+func WithClock(clock Clock) ScheduleOption {
+	return func(config *scheduleConfig) {
+		config.clock = clock
+	}
+}
+
+// WithJitter delays every run by a random duration below jitter, to spread
+// the load of schedules that would otherwise run at the same time. The jitter
+// should be shorter than the interval of the schedule.
+func WithJitter(jitter time.Duration) ScheduleOption {
+	return func(config *scheduleConfig) {
+		config.jitter = jitter
+	}
+}
+
+// SkipOverlapping skips a tick if the previous run has not returned yet.
+// By default, every tick starts a run, even if the previous one is running.
+func SkipOverlapping() ScheduleOption {
+	return func(config *scheduleConfig) {
+		config.skipOverlapping = true
+	}
+}
+
+// CatchUp runs once for every missed tick when the schedule falls behind, for
+// example after the process was suspended, up to limit runs. By default, missed
+// ticks are dropped and a single run is started. Combined with
+// SkipOverlapping, ticks missed while a run is in progress are run after it,
+// one at a time, and at most limit of them are owed at any time.
+func CatchUp(limit int) ScheduleOption {
+	return func(config *scheduleConfig) {
+		config.catchUp = max(limit, 1)
+	}
+}
+
+// WithErrorHandler calls handler with the error of every run that fails or
+// panics. By default, errors of runs are dropped.
+func WithErrorHandler(handler func(err error)) ScheduleOption {
+	return func(config *scheduleConfig) {
+		config.errorHandler = handler
+	}
+}
+
+func newScheduleConfig(options []ScheduleOption) scheduleConfig {
+	config := scheduleConfig{
+		clock: systemClock{},
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	return config
+}
+
+func (self scheduleConfig) randomJitter() time.Duration {
+	if self.jitter <= 0 {
+		return 0
+	}
+
+	return rand.N(self.jitter) // nolint:gosec // reason: jitter does not need a secure random source
+}
+
+// After runs fn once delay elapsed, using Run. The returned channel receives
+// the error of fn, or ctx.Err() if ctx is done first.
+func After(ctx context.Context, delay time.Duration, fn RunFn, options ...ScheduleOption) <-chan error {
+	config := newScheduleConfig(options)
+
+	return Run(ctx, func(ctx context.Context) error {
+		timer := config.clock.NewTimer(delay + config.randomJitter())
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C():
+			return fn(ctx)
+		}
+	})
+}
+
+// Every runs fn every interval, using Run for every run so each one has a
+// freshly localized Context. The first run starts after interval. Errors of
+// runs do not stop the schedule, see WithErrorHandler.
+//
+// The schedule stops when ctx is done. The returned channel then receives
+// ctx.Err() once every run has returned. Panics if interval is not positive.
+func Every(ctx context.Context, interval time.Duration, fn RunFn, options ...ScheduleOption) <-chan error {
+	if interval <= 0 {
+		panic("non-positive interval for gofunc.Every")
+	}
+
+	config := newScheduleConfig(options)
+	start := config.clock.Now()
+
+	return config.schedule(ctx, func(after time.Time) time.Time {
+		elapsed := after.Sub(start)
+		if elapsed < 0 {
+			return start.Add(interval)
+		}
+
+		return start.Add((elapsed/interval + 1) * interval)
+	}, fn)
+}
+
+// Cron runs fn at the times matching the cron expression, see ParseCron, in
+// the location of the clock. Runs behave as for Every. Returns ErrInvalidCron
+// if expression is malformed.
+func Cron(ctx context.Context, expression string, fn RunFn, options ...ScheduleOption) (<-chan error, error) {
+	schedule, err := ParseCron(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	config := newScheduleConfig(options)
+
+	return config.schedule(ctx, schedule.Next, fn), nil
+}
+
+// schedule runs fn at the times returned by next, until ctx is done. A zero
+// time means there is no next run.
+func (self scheduleConfig) schedule(ctx context.Context, next func(after time.Time) time.Time, fn RunFn) <-chan error {
+	return Run(ctx, func(ctx context.Context) error {
+		var (
+			finished = make(chan error)
+			running  int
+			pending  int
+			timer    Timer
+			fire     <-chan time.Time
+		)
+
+		start := func() {
+			running++
+			result := Run(ctx, fn)
+			go func() {
+				finished <- <-result
+			}()
+		}
+
+		finish := func(err error) {
+			running--
+			if err != nil && self.errorHandler != nil {
+				self.errorHandler(err)
+			}
+		}
+
+		tick := next(self.clock.Now())
+		for {
+			if timer == nil && !tick.IsZero() {
+				timer = self.clock.NewTimer(tick.Sub(self.clock.Now()) + self.randomJitter())
+				fire = timer.C()
+			}
+
+			select {
+			case <-ctx.Done():
+				if timer != nil {
+					timer.Stop()
+				}
+				for running > 0 {
+					finish(<-finished)
+				}
+
+				return ctx.Err()
+			case err := <-finished:
+				finish(err)
+				if pending > 0 {
+					pending--
+					start()
+				}
+			case <-fire:
+				timer, fire = nil, nil
+
+				now := self.clock.Now()
+				missed := 0
+				for !tick.IsZero() && !tick.After(now) {
+					missed++
+					tick = next(tick)
+				}
+				if missed == 0 {
+					// Fired early, wait for the tick again.
+					continue
+				}
+
+				owed := 1
+				if self.catchUp > 0 {
+					owed = min(missed, self.catchUp)
+				}
+
+				if !self.skipOverlapping {
+					for ; owed > 0; owed-- {
+						start()
+					}
+				} else {
+					if running == 0 {
+						start()
+						owed--
+					}
+					pending = min(pending+owed, self.catchUp)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,363 @@
+package gofunc_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+type runKey struct{}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	deadline time.Time
+	c        chan time.Time
+	done     bool
+}
+
+func (self *fakeTimer) C() <-chan time.Time {
+	return self.c
+}
+
+func (self *fakeTimer) Stop() bool {
+	self.clock.mutex.Lock()
+	defer self.clock.mutex.Unlock()
+
+	stopped := !self.done
+	self.done = true
+
+	return stopped
+}
+
+// fakeClock only moves when advanced.
+type fakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{
+		now: now,
+	}
+}
+
+func (self *fakeClock) Now() time.Time {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return self.now
+}
+
+func (self *fakeClock) NewTimer(duration time.Duration) gofunc.Timer {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	timer := &fakeTimer{
+		clock:    self,
+		deadline: self.now.Add(duration),
+		c:        make(chan time.Time, 1),
+	}
+	self.timers = append(self.timers, timer)
+	self.fire()
+
+	return timer
+}
+
+func (self *fakeClock) Advance(duration time.Duration) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.now = self.now.Add(duration)
+	self.fire()
+}
+
+// fire the timers that are due. Requires the mutex to be held.
+func (self *fakeClock) fire() {
+	active := self.timers[:0]
+	for _, timer := range self.timers {
+		switch {
+		case timer.done:
+		case !timer.deadline.After(self.now):
+			timer.done = true
+			timer.c <- self.now
+		default:
+			active = append(active, timer)
+		}
+	}
+	self.timers = active
+}
+
+// waitTimer waits until a timer is pending.
+func (self *fakeClock) waitTimer(t *testing.T) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		self.mutex.Lock()
+		defer self.mutex.Unlock()
+
+		for _, timer := range self.timers {
+			if !timer.done {
+				return true
+			}
+		}
+
+		return false
+	}, time.Second, time.Millisecond)
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+
+	select {
+	case value := <-ch:
+		return value
+	case <-time.After(time.Second):
+		assert.Fail(t, "nothing received")
+
+		var zero T
+
+		return zero
+	}
+}
+
+func assertNothing[T any](t *testing.T, ch <-chan T) {
+	t.Helper()
+
+	select {
+	case <-ch:
+		assert.Fail(t, "unexpected receive")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func Test_Every(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	runs := make(chan time.Time, 10)
+	done := gofunc.Every(ctx, time.Minute, func(ctx context.Context) error {
+		// Every run starts with fresh local values.
+		assert.Nil(t, ctx.Value(runKey{}))
+		context.WithLocalValue(ctx, runKey{}, true)
+		runs <- clock.Now()
+
+		return nil
+	}, gofunc.WithClock(clock))
+
+	for minute := 1; minute <= 3; minute++ {
+		clock.waitTimer(t)
+		clock.Advance(time.Minute)
+		assert.Equal(t, time.Date(2026, 1, 1, 0, minute, 0, 0, time.UTC), receive(t, runs))
+	}
+
+	cancel()
+	assert.ErrorIs(t, receive(t, done), context.Canceled)
+	assertNothing(t, runs)
+}
+
+func Test_Every_missed_ticks(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		options  []gofunc.ScheduleOption
+		expected int
+	}{
+		{name: "drop", expected: 1},
+		{name: "catch up", options: []gofunc.ScheduleOption{gofunc.CatchUp(10)}, expected: 3},
+		{name: "catch up limited", options: []gofunc.ScheduleOption{gofunc.CatchUp(2)}, expected: 2},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := newFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			runs := make(chan struct{}, 10)
+			gofunc.Every(ctx, time.Minute, func(ctx context.Context) error {
+				runs <- struct{}{}
+
+				return nil
+			}, append(test.options, gofunc.WithClock(clock))...)
+
+			clock.waitTimer(t)
+			clock.Advance(3*time.Minute + time.Second)
+
+			for run := 0; run < test.expected; run++ {
+				receive(t, runs)
+			}
+			assertNothing(t, runs)
+
+			// Back on schedule.
+			clock.waitTimer(t)
+			clock.Advance(time.Minute)
+			receive(t, runs)
+		})
+	}
+}
+
+func Test_Every_SkipOverlapping(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		options  []gofunc.ScheduleOption
+		expected int32
+	}{
+		{name: "overlap", expected: 2},
+		{name: "skip", options: []gofunc.ScheduleOption{gofunc.SkipOverlapping()}, expected: 1},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := newFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+			ctx, cancel := context.WithCancel(context.Background())
+
+			var started atomic.Int32
+			release := make(chan struct{})
+			done := gofunc.Every(ctx, time.Minute, func(ctx context.Context) error {
+				started.Add(1)
+				<-release
+
+				return nil
+			}, append(test.options, gofunc.WithClock(clock))...)
+
+			for tick := 0; tick < 2; tick++ {
+				clock.waitTimer(t)
+				clock.Advance(time.Minute)
+			}
+			clock.waitTimer(t)
+
+			assert.Eventually(t, func() bool {
+				return started.Load() == test.expected
+			}, time.Second, time.Millisecond)
+
+			cancel()
+			close(release)
+			assert.ErrorIs(t, receive(t, done), context.Canceled)
+			assert.Equal(t, test.expected, started.Load())
+		})
+	}
+}
+
+func Test_Every_SkipOverlapping_CatchUp(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	runs := make(chan struct{}, 10)
+	gofunc.Every(ctx, time.Minute, func(ctx context.Context) error {
+		runs <- struct{}{}
+		<-release
+
+		return nil
+	}, gofunc.SkipOverlapping(), gofunc.CatchUp(2), gofunc.WithClock(clock))
+
+	clock.waitTimer(t)
+	clock.Advance(time.Minute)
+	receive(t, runs)
+
+	// Ticks missed while the first run is in progress are owed, up to the limit.
+	for tick := 0; tick < 5; tick++ {
+		clock.waitTimer(t)
+		clock.Advance(time.Minute)
+	}
+	clock.waitTimer(t)
+
+	close(release)
+	receive(t, runs)
+	receive(t, runs)
+	assertNothing(t, runs)
+}
+
+func Test_Every_errors(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var run atomic.Int32
+	errs := make(chan error, 2)
+	gofunc.Every(ctx, time.Second, func(ctx context.Context) error {
+		if run.Add(1) == 1 {
+			return errTest
+		}
+		panic("boom")
+	}, gofunc.WithClock(clock), gofunc.WithJitter(time.Millisecond), gofunc.WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+
+	clock.waitTimer(t)
+	clock.Advance(time.Second + time.Millisecond)
+	assert.ErrorIs(t, receive(t, errs), errTest)
+
+	clock.waitTimer(t)
+	clock.Advance(time.Second + time.Millisecond)
+	assert.ErrorIs(t, receive(t, errs), errors.ErrPanic)
+}
+
+func Test_After(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	done := gofunc.After(context.Background(), time.Hour, func(ctx context.Context) error {
+		return errTest
+	}, gofunc.WithClock(clock))
+
+	clock.waitTimer(t)
+	assertNothing(t, done)
+	clock.Advance(time.Hour)
+	assert.ErrorIs(t, receive(t, done), errTest)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := gofunc.After(ctx, time.Hour, func(ctx context.Context) error {
+		return nil
+	}, gofunc.WithClock(clock))
+	cancel()
+	assert.ErrorIs(t, receive(t, canceled), context.Canceled)
+}
+
+func Test_Cron(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock(time.Date(2026, 1, 1, 0, 0, 30, 0, time.UTC))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := make(chan time.Time, 10)
+	_, err := gofunc.Cron(ctx, "*/15 * * * *", func(ctx context.Context) error {
+		runs <- clock.Now()
+
+		return nil
+	}, gofunc.WithClock(clock))
+	assert.NoError(t, err)
+
+	clock.waitTimer(t)
+	clock.Advance(14 * time.Minute)
+	assertNothing(t, runs)
+
+	clock.waitTimer(t)
+	clock.Advance(30 * time.Second)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 15, 0, 0, time.UTC), receive(t, runs))
+
+	_, err = gofunc.Cron(ctx, "* * *", func(ctx context.Context) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, gofunc.ErrInvalidCron)
+}
@@ -114,7 +114,7 @@ func parseUintBytes(s []byte, base int, bitSize int) (n uint64, err error) {
 		}
 
 	default:
-		err = errors.New("allyourbase", "invalid base "+strconv.Itoa(base))
+		err = errors.New("invalid base %d", base)
 
 		goto Error
 	}
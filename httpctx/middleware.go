@@ -0,0 +1,183 @@
+// Package httpctx integrates Context with net/http.
+package httpctx
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+const (
+	// RequestIDHeader is the header used to read the ID of a request.
+	RequestIDHeader = "X-Request-Id"
+	// RouteLabel is the pprof label holding the route of the request, see gofunc.RegisterLabel.
+	RouteLabel = "route"
+	// UnmatchedRoute is the route of requests that match no configured route.
+	// Raw paths are never used as routes since they are unbounded.
+	UnmatchedRoute = "unmatched"
+)
+
+// nolint:gochecknoinits // reason: labels goroutines of requests by route on import
+func init() {
+	if err := gofunc.RegisterLabel(RouteLabel, requestRoute); err != nil {
+		panic(err)
+	}
+}
+
+// requestRoute returns the route of the request served on ctx.
+func requestRoute(ctx context.Context) string {
+	info, _ := Request(ctx)
+
+	return info.Route
+}
+
+// RequestInfo is the metadata of an incoming request stored as an immutable
+// value on the request Context.
+type RequestInfo struct {
+	Method     string
+	Path       string
+	Route      string
+	RemoteAddr string
+	RequestID  string
+	Received   time.Time
+}
+
+type requestInfoKey struct{}
+
+// Request returns the metadata of the request stored by Middleware.
+func Request(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+
+	return info, ok
+}
+
+// From returns the Context of the request.
+//
+// The Context is localized to the goroutine serving the request when the
+// request passed through Middleware. Otherwise, it is the unlocalized stdlib
+// Context of the request and local values cannot be set on it.
+func From(r *http.Request) context.Context {
+	return r.Context()
+}
+
+// Option configures Middleware.
+type Option func(*config)
+
+type routeTimeout struct {
+	route   string
+	timeout time.Duration
+}
+
+type config struct {
+	timeout time.Duration
+	routes  []routeTimeout
+}
+
+// WithTimeout applies timeout to every request that does not match a route
+// configured using WithRouteTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(config *config) {
+		config.timeout = timeout
+	}
+}
+
+// WithRouteTimeout applies timeout to requests matching route.
+//
+// Routes follow the matching of http.ServeMux paths: a route ending in a
+// slash matches every path it prefixes, otherwise the path must match exactly.
+// The longest matching route wins.
+func WithRouteTimeout(route string, timeout time.Duration) Option {
+	return func(config *config) {
+		config.routes = append(config.routes, routeTimeout{
+			route:   route,
+			timeout: timeout,
+		})
+	}
+}
+
+// Middleware wraps next so that every request is served on a Context
+// localized to the serving goroutine.
+//
+// The request Context is named after the matched route (or UnmatchedRoute),
+// carries a RequestInfo, and is canceled when the client disconnects, the
+// configured timeout elapses, or next returns. Use From to retrieve it.
+//
+// Requests sent using Transport additionally restore the deadline budget and
+// the values registered using Propagate of the calling Context.
+func Middleware(next http.Handler, options ...Option) http.Handler {
+	config := &config{}
+	for _, option := range options {
+		option(config)
+	}
+
+	// Longest routes first so the first match is the most specific.
+	sort.SliceStable(config.routes, func(i, j int) bool {
+		return len(config.routes[i].route) > len(config.routes[j].route)
+	})
+
+	return &middleware{
+		next:   next,
+		config: config,
+	}
+}
+
+type middleware struct {
+	next   http.Handler
+	config *config
+}
+
+func (self *middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := Request(r.Context()); ok {
+		// Already served by a Middleware.
+		self.next.ServeHTTP(w, r)
+
+		return
+	}
+
+	received := time.Now()
+	route, timeout := self.match(r.URL.Path)
+
+	ctx := context.Localize(r.Context())
+	ctx = context.WithName(ctx, r.Method+" "+route)
+	ctx = context.WithValue(ctx, requestInfoKey{}, RequestInfo{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Route:      route,
+		RemoteAddr: r.RemoteAddr,
+		RequestID:  r.Header.Get(RequestIDHeader),
+		Received:   received,
+	})
+	ctx = extractValues(ctx, r)
+
+	// The deadline sent by the caller applies unless the route timeout is sooner.
+	deadline, hasDeadline := extractDeadline(r, received)
+	if timeout > 0 && (!hasDeadline || received.Add(timeout).Before(deadline)) {
+		deadline, hasDeadline = received.Add(timeout), true
+	}
+
+	var cancel context.CancelFunc
+	if hasDeadline {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	self.next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// match returns the route matching path and its timeout.
+// If no route matches, UnmatchedRoute is returned with the default timeout.
+func (self *middleware) match(path string) (string, time.Duration) {
+	for _, route := range self.config.routes {
+		if route.route == path || (strings.HasSuffix(route.route, "/") && strings.HasPrefix(path, route.route)) {
+			return route.route, route.timeout
+		}
+	}
+
+	return UnmatchedRoute, self.config.timeout
+}
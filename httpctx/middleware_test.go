@@ -0,0 +1,144 @@
+package httpctx_test
+
+import (
+	gocontext "context" // nolint:depguard // reason: client side requests use the stdlib context
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+	"github.com/wspowell/context/httpctx"
+)
+
+type localKey struct{}
+
+func Test_Middleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		info     httpctx.RequestInfo
+		found    bool
+		local    any
+		name     string
+		deadline time.Time
+		hasLimit bool
+	)
+
+	handler := httpctx.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := httpctx.From(r)
+
+		context.WithLocalValue(ctx, localKey{}, "local")
+		local = ctx.Value(localKey{})
+		info, found = httpctx.Request(ctx)
+		name = context.NamePath(ctx)
+		deadline, hasLimit = ctx.Deadline()
+	}), httpctx.WithTimeout(time.Minute), httpctx.WithRouteTimeout("/api/", time.Second))
+
+	request := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	request.Header.Set(httpctx.RequestIDHeader, "abc")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Equal(t, "local", local)
+	assert.True(t, found)
+	assert.Equal(t, http.MethodGet, info.Method)
+	assert.Equal(t, "/api/users", info.Path)
+	assert.Equal(t, "/api/", info.Route)
+	assert.Equal(t, "abc", info.RequestID)
+	assert.False(t, info.Received.IsZero())
+	assert.Equal(t, "GET /api/", name)
+	assert.True(t, hasLimit)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, time.Second)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/other", nil))
+
+	assert.Equal(t, "/other", info.Path)
+	assert.Equal(t, httpctx.UnmatchedRoute, info.Route)
+	assert.Equal(t, "POST unmatched", name)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+}
+
+func Test_Middleware_route_exact(t *testing.T) {
+	t.Parallel()
+
+	var hasDeadline bool
+
+	handler := httpctx.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = httpctx.From(r).Deadline()
+	}), httpctx.WithRouteTimeout("/health", time.Second))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.True(t, hasDeadline)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health/deep", nil))
+	assert.False(t, hasDeadline)
+}
+
+func Test_Middleware_route_label(t *testing.T) {
+	t.Parallel()
+
+	var labels []string
+
+	handler := httpctx.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		labels = gofunc.Labels(httpctx.From(r))
+	}), httpctx.WithRouteTimeout("/users/", time.Second))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	assert.Equal(t, []string{"context", "GET /users/", httpctx.RouteLabel, "/users/"}, labels)
+}
+
+func Test_Middleware_nested(t *testing.T) {
+	t.Parallel()
+
+	var served bool
+
+	handler := httpctx.Middleware(httpctx.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		context.WithLocalValue(httpctx.From(r), localKey{}, "local")
+		served = true
+	})))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.True(t, served)
+}
+
+func Test_Middleware_client_disconnect(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	stopped := make(chan error, 1)
+
+	server := httptest.NewServer(httpctx.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		done := gofunc.Run(httpctx.From(r), func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+
+			return ctx.Err()
+		})
+		stopped <- <-done
+	})))
+	defer server.Close()
+
+	clientCtx, clientCancel := gocontext.WithCancel(gocontext.Background())
+	request, err := http.NewRequestWithContext(clientCtx, http.MethodGet, server.URL, nil)
+	assert.Nil(t, err)
+
+	go func() {
+		<-started
+		clientCancel()
+	}()
+
+	response, err := http.DefaultClient.Do(request)
+	if err == nil {
+		response.Body.Close()
+	}
+
+	select {
+	case err := <-stopped:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "goroutine not canceled on client disconnect")
+	}
+}
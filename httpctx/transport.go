@@ -0,0 +1,139 @@
+package httpctx
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/propagate"
+)
+
+const (
+	// DeadlineHeader carries the remaining deadline budget of a request in milliseconds.
+	DeadlineHeader = "X-Request-Deadline-Ms"
+	// SentAtHeader carries the time a request was sent in Unix milliseconds.
+	// Used to subtract the transit time from the deadline budget.
+	SentAtHeader = "X-Request-Sent-At"
+	// MaxTransit is the most transit time subtracted from a deadline budget.
+	// Larger transit times are assumed to be caused by the clock of the
+	// client running behind and would otherwise consume the whole budget.
+	MaxTransit = time.Second
+)
+
+// Propagate registers key so that its immutable value is sent by Transport in
+// header and restored by Middleware on the receiving side. It is shorthand for
+// registering key with the propagate package under the header name.
+//
+// Local values are never sent, see context.ImmutableValue.
+//
+// Like http.Handle, Propagate panics if the registration fails, see
+// propagate.Register.
+func Propagate[K comparable, V any](key K, header string, encode func(value V) string, decode func(header string) (V, error)) {
+	codec := propagate.Func(func(value V) (string, error) {
+		return encode(value), nil
+	}, decode)
+
+	if err := propagate.Register(http.CanonicalHeaderKey(header), key, codec); err != nil {
+		panic(err)
+	}
+}
+
+// Transport is an http.RoundTripper that propagates the Context of outgoing
+// requests. The remaining deadline budget is sent in DeadlineHeader and
+// values registered using Propagate, or with the propagate package, are sent
+// in headers named after their registered names.
+type Transport struct {
+	// Base is the RoundTripper used to send requests.
+	// If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (self *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	var ctx context.Context = r.Context()
+
+	// A RoundTripper must not modify the request.
+	r = r.Clone(ctx)
+
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			closeBody(r)
+
+			if err := ctx.Err(); err != nil {
+				return nil, err // nolint:wrapcheck // reason: context error returned as is
+			}
+
+			return nil, context.DeadlineExceeded
+		}
+
+		r.Header.Set(DeadlineHeader, strconv.FormatInt(remaining.Milliseconds(), 10))
+		r.Header.Set(SentAtHeader, strconv.FormatInt(time.Now().UnixMilli(), 10))
+	}
+
+	if err := propagate.Inject(ctx, propagate.HeaderCarrier(r.Header)); err != nil {
+		closeBody(r)
+
+		return nil, err // nolint:wrapcheck // reason: propagate errors returned as is
+	}
+
+	base := self.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return base.RoundTrip(r) // nolint:wrapcheck // reason: transport errors returned as is
+}
+
+// maxBudgetMs is the largest deadline budget representable as a time.Duration.
+const maxBudgetMs = math.MaxInt64 / int64(time.Millisecond)
+
+// extractDeadline returns the deadline sent by Transport, adjusted for the
+// time the request spent in transit, up to MaxTransit.
+func extractDeadline(r *http.Request, now time.Time) (time.Time, bool) {
+	budgetHeader := r.Header.Get(DeadlineHeader)
+	if budgetHeader == "" {
+		return time.Time{}, false
+	}
+
+	budgetMs, err := strconv.ParseInt(budgetHeader, 10, 64)
+	if err != nil || budgetMs < 0 {
+		return time.Time{}, false
+	}
+	// Clamp budgets that would overflow a time.Duration.
+	if budgetMs > maxBudgetMs {
+		budgetMs = maxBudgetMs
+	}
+	budget := time.Duration(budgetMs) * time.Millisecond
+
+	if sentAtHeader := r.Header.Get(SentAtHeader); sentAtHeader != "" {
+		if sentAtMs, err := strconv.ParseInt(sentAtHeader, 10, 64); err == nil {
+			// Ignore negative transit times and clamp large ones, both caused by clock skew.
+			if transit := now.Sub(time.UnixMilli(sentAtMs)); transit > 0 {
+				if transit > MaxTransit {
+					transit = MaxTransit
+				}
+				budget -= transit
+			}
+		}
+	}
+
+	return now.Add(budget), true
+}
+
+// extractValues returns ctx with the propagated values sent in the headers of r.
+// Malformed values are dropped rather than failing the request.
+func extractValues(ctx context.Context, r *http.Request) context.Context {
+	ctx, _ = propagate.Extract(ctx, propagate.HeaderCarrier(r.Header)) // nolint:errcheck // reason: malformed values are dropped
+
+	return ctx
+}
+
+// closeBody of a request that will not be sent, as required of a RoundTripper.
+func closeBody(r *http.Request) {
+	if r.Body != nil {
+		_ = r.Body.Close()
+	}
+}
@@ -0,0 +1,149 @@
+package httpctx_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/httpctx"
+)
+
+type correlationIDKey struct{}
+
+type correlationID string
+
+type localOnlyKey struct{}
+
+// nolint:gochecknoinits // reason: registers propagated test keys once
+func init() {
+	httpctx.Propagate(correlationIDKey{}, "X-Correlation-Id",
+		func(value correlationID) string { return string(value) },
+		func(header string) (correlationID, error) { return correlationID(header), nil },
+	)
+	httpctx.Propagate(localOnlyKey{}, "X-Local-Only",
+		func(value string) string { return value },
+		func(header string) (string, error) { return header, nil },
+	)
+}
+
+func Test_Transport(t *testing.T) {
+	t.Parallel()
+
+	type received struct {
+		correlationID any
+		localOnly     any
+		deadline      time.Time
+		hasDeadline   bool
+		header        http.Header
+	}
+	receivedCh := make(chan received, 1)
+
+	server := httptest.NewServer(httpctx.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := httpctx.From(r)
+		deadline, hasDeadline := ctx.Deadline()
+		receivedCh <- received{
+			correlationID: ctx.Value(correlationIDKey{}),
+			localOnly:     ctx.Value(localOnlyKey{}),
+			deadline:      deadline,
+			hasDeadline:   hasDeadline,
+			header:        r.Header,
+		}
+	})))
+	defer server.Close()
+
+	client := &http.Client{Transport: &httpctx.Transport{}}
+
+	ctx := context.WithValue(context.Background(), correlationIDKey{}, correlationID("abc-123"))
+	context.WithLocalValue(ctx, localOnlyKey{}, "secret")
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	assert.Nil(t, err)
+
+	response, err := client.Do(request)
+	assert.Nil(t, err)
+	response.Body.Close()
+
+	// The original request is not modified.
+	assert.Empty(t, request.Header.Get(httpctx.DeadlineHeader))
+
+	result := <-receivedCh
+	assert.Equal(t, correlationID("abc-123"), result.correlationID)
+	assert.Nil(t, result.localOnly)
+	assert.Empty(t, result.header.Get("X-Local-Only"))
+	assert.True(t, result.hasDeadline)
+
+	deadline, _ := ctx.Deadline()
+	assert.WithinDuration(t, deadline, result.deadline, 100*time.Millisecond)
+}
+
+func Test_Transport_expired(t *testing.T) {
+	t.Parallel()
+
+	client := &http.Client{Transport: &httpctx.Transport{}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost:1", nil)
+	assert.Nil(t, err)
+
+	response, err := client.Do(request)
+	if response != nil {
+		response.Body.Close()
+	}
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func Test_Middleware_deadline_transit(t *testing.T) {
+	t.Parallel()
+
+	var deadline time.Time
+
+	handler := httpctx.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, _ = httpctx.From(r).Deadline()
+	}), httpctx.WithTimeout(time.Hour))
+
+	// Sent half a second ago with a five second budget.
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(httpctx.DeadlineHeader, "5000")
+	request.Header.Set(httpctx.SentAtHeader, strconv.FormatInt(time.Now().Add(-500*time.Millisecond).UnixMilli(), 10))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.WithinDuration(t, time.Now().Add(4500*time.Millisecond), deadline, 100*time.Millisecond)
+
+	// Client clock an hour behind: transit is clamped.
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(httpctx.DeadlineHeader, "5000")
+	request.Header.Set(httpctx.SentAtHeader, strconv.FormatInt(time.Now().Add(-time.Hour).UnixMilli(), 10))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.WithinDuration(t, time.Now().Add(5*time.Second-httpctx.MaxTransit), deadline, 100*time.Millisecond)
+
+	// Budget too large for a time.Duration: clamped rather than overflowing.
+	handler = httpctx.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, _ = httpctx.From(r).Deadline()
+	}))
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(httpctx.DeadlineHeader, "9223372036854775807")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.True(t, deadline.After(time.Now().Add(100*365*24*time.Hour)))
+
+	// Route timeout sooner than the sent budget.
+	handler = httpctx.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, _ = httpctx.From(r).Deadline()
+	}), httpctx.WithTimeout(time.Second))
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(httpctx.DeadlineHeader, "5000")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+}
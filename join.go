@@ -0,0 +1,19 @@
+package context
+
+// join calls Join() on every local value implementing it.
+// Values are joined outside of the lock so they may access the Context.
+func (self *localCtx) join() {
+	var joiners []interface{ Join() }
+
+	self.localsMutex.RLock()
+	for _, value := range self.localValues {
+		if joiner, ok := value.(interface{ Join() }); ok {
+			joiners = append(joiners, joiner)
+		}
+	}
+	self.localsMutex.RUnlock()
+
+	for _, joiner := range joiners {
+		joiner.Join()
+	}
+}
@@ -15,7 +15,7 @@ func Localize(ctx Context) Context {
 
 	if local, ok := ctx.Value(localsKey{}).(*localCtx); ok {
 		if local.goroutineOrigin.isSameGoroutine() {
-			panic("context localized twice in the same goroutine")
+			panic(violation("context localized twice in the same goroutine" + describe(ctx)))
 		}
 
 		// Values are shadowed by the local context to prevent access to any locals in a parent context.
@@ -48,13 +48,32 @@ func Localize(ctx Context) Context {
 func WithLocalValue(parent Context, key any, value any) {
 	if local, ok := parent.Value(localsKey{}).(*localCtx); ok {
 		if !local.goroutineOrigin.isSameGoroutine() {
-			panic("context not localized to the current goroutine")
+			panic(violation("context not localized to the current goroutine" + describe(parent)))
 		}
 
+		local.localsMutex.Lock()
 		local.localValues[key] = value
+		local.localsMutex.Unlock()
+
+		return
+	}
+
+	panic(violation("context not localized to the current goroutine" + describe(parent)))
+}
+
+// Join notifies the local values of ctx that implement `Join()` that the
+// goroutine ctx is localized to is about to exit, for example to flush or merge
+// the state the goroutine gathered. Join must be called by that goroutine.
+func Join(ctx Context) {
+	if local, ok := ctx.Value(localsKey{}).(*localCtx); ok {
+		if !local.goroutineOrigin.isSameGoroutine() {
+			panic(violation("context joined outside original goroutine" + describe(ctx)))
+		}
+
+		local.join()
 
 		return
 	}
 
-	panic("context not localized to the current goroutine")
+	panic(violation("context not localized to the current goroutine" + describe(ctx)))
 }
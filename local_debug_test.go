@@ -9,6 +9,8 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/wspowell/context"
 )
 
@@ -118,3 +120,53 @@ func Test_WithLocalValue_missing_Localize(t *testing.T) {
 	}()
 	wg.Wait()
 }
+
+func Test_Localize_panic_names_context(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Localize(context.WithName(context.TODO(), "checkout"))
+	context.WithLocalValue(ctx, localContextKey{}, localValue)
+
+	var message any
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			message = recover()
+		}()
+
+		ctx.Value(localContextKey{})
+	}()
+	wg.Wait()
+
+	err, ok := message.(error)
+	assert.True(t, ok)
+	assert.Equal(t, "localized value accessed outside original goroutine (checkout)", err.Error())
+	assert.Equal(t, "CTX2", context.InternalCode(err))
+}
+
+func Test_Join_outside_original_goroutine(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Localize(context.WithName(context.TODO(), "worker"))
+
+	var message any
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			message = recover()
+		}()
+
+		context.Join(ctx)
+	}()
+	wg.Wait()
+
+	err, ok := message.(error)
+	assert.True(t, ok)
+	assert.Equal(t, "context joined outside original goroutine (worker)", err.Error())
+}
@@ -44,10 +44,25 @@ func Localize(ctx Context) Context {
 // as a value local to the current goroutine.
 func WithLocalValue(parent Context, key any, value any) {
 	if local, ok := parent.Value(localsKey{}).(*localCtx); ok {
+		local.localsMutex.Lock()
 		local.localValues[key] = value
+		local.localsMutex.Unlock()
+
+		return
+	}
+
+	panic(violation("context not localized to the current goroutine" + describe(parent)))
+}
+
+// Join notifies the local values of ctx that implement `Join()` that the
+// goroutine ctx is localized to is about to exit, for example to flush or merge
+// the state the goroutine gathered. Join must be called by that goroutine.
+func Join(ctx Context) {
+	if local, ok := ctx.Value(localsKey{}).(*localCtx); ok {
+		local.join()
 
 		return
 	}
 
-	panic("context not localized to the current goroutine")
+	panic(violation("context not localized to the current goroutine" + describe(ctx)))
 }
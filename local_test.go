@@ -184,3 +184,47 @@ func Test_Context_ThreadSafety_Correct_Usage(t *testing.T) {
 		t.Errorf("expected localValueContextKey{} == 15")
 	}
 }
+
+func Test_ImmutableValue(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	ctx = context.WithValue(ctx, immutableContextKey{}, immutableValue)
+	ctx = context.WithValue(ctx, duplicateContextKey{}, immutableValue)
+	ctx = context.Localize(ctx)
+	ctx, cancel := context.WithCancel(context.WithName(ctx, "named"))
+	defer cancel()
+
+	context.WithLocalValue(ctx, localContextKey{}, localValue)
+	context.WithLocalValue(ctx, duplicateContextKey{}, duplicateValue)
+
+	assert.Equal(t, duplicateValue, ctx.Value(duplicateContextKey{}))
+	assert.Equal(t, immutableValue, context.ImmutableValue(ctx, duplicateContextKey{}))
+	assert.Equal(t, immutableValue, context.ImmutableValue(ctx, immutableContextKey{}))
+	assert.Nil(t, context.ImmutableValue(ctx, localContextKey{}))
+}
+
+type joinCounter struct {
+	joined *int
+}
+
+func (self joinCounter) Join() {
+	*self.joined++
+}
+
+func Test_Join(t *testing.T) {
+	t.Parallel()
+
+	var joined int
+
+	ctx := context.Background()
+	context.WithLocalValue(ctx, localContextKey{}, localValue)
+	context.WithLocalValue(ctx, localValueContextKey{}, joinCounter{joined: &joined})
+
+	context.Join(ctx)
+	assert.Equal(t, 1, joined)
+
+	assert.Panics(t, func() {
+		context.Join(context.TODO())
+	})
+}
@@ -21,13 +21,16 @@ func (self *localCtx) Value(key any) any {
 	if key == (localsKey{}) {
 		return self
 	}
+	if _, ok := key.(immutableKey); ok {
+		return self.Context.Value(key)
+	}
 
 	self.localsMutex.RLock()
 	localValue, exists := self.localValues[key]
 	self.localsMutex.RUnlock()
 	if exists {
 		if !self.goroutineOrigin.isSameGoroutine() {
-			panic("localized value accessed outside original goroutine")
+			panic(violation("localized value accessed outside original goroutine" + describe(self.Context)))
 		}
 
 		return localValue
@@ -35,3 +38,8 @@ func (self *localCtx) Value(key any) any {
 
 	return self.Context.Value(key)
 }
+
+// goroutine returns the ID of the goroutine the context is localized to.
+func (self *localCtx) goroutine() uint64 {
+	return uint64(self.goroutineOrigin)
+}
@@ -21,6 +21,9 @@ func (self *localCtx) Value(key any) any {
 	if key == (localsKey{}) {
 		return self
 	}
+	if _, ok := key.(immutableKey); ok {
+		return self.Context.Value(key)
+	}
 
 	self.localsMutex.RLock()
 	localValue, exists := self.localValues[key]
@@ -31,3 +34,9 @@ func (self *localCtx) Value(key any) any {
 
 	return self.Context.Value(key)
 }
+
+// goroutine returns the ID of the goroutine the context is localized to.
+// Goroutines are not tracked in release builds.
+func (self *localCtx) goroutine() uint64 {
+	return 0
+}
@@ -0,0 +1,121 @@
+// Package memo memoizes lookups for the lifetime of a request.
+//
+// The cache is stored as an immutable value on the root Context of a request
+// using WithCache, so every goroutine that Localizes the Context shares it.
+// Concurrent loads of the same key are deduplicated. The cache is discarded
+// when the root Context is canceled.
+package memo
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/singleflight"
+)
+
+// ErrTypeMismatch is returned by Get when the value cached for a key is not
+// of the requested type.
+var ErrTypeMismatch = errors.New("memoized value type mismatch")
+
+type cacheKey struct{}
+
+type cache struct {
+	mutex sync.RWMutex
+	// entries is nil once the cache is discarded.
+	entries map[any]any
+	loads   singleflight.Group[any, any]
+}
+
+// WithCache returns a copy of ctx carrying a new, empty cache. The cache is
+// discarded when ctx is canceled.
+func WithCache(ctx context.Context) context.Context {
+	memo := &cache{
+		entries: map[any]any{},
+	}
+
+	if done := ctx.Done(); done != nil {
+		go func() {
+			<-done
+			memo.discard()
+		}()
+	}
+
+	return context.WithValue(ctx, cacheKey{}, memo)
+}
+
+// Get the value of key from the cache of ctx, calling loader to load it if it
+// is not cached yet. Concurrent calls for the same key share one call of
+// loader, see singleflight.Group. Errors are not cached. If ctx has no cache,
+// loader is called every time.
+//
+// The key must be comparable and should not be of a built-in type, to avoid
+// collisions between packages, as for context.WithValue.
+func Get[T any](ctx context.Context, key any, loader func(ctx context.Context) (T, error)) (T, error) {
+	memo, ok := ctx.Value(cacheKey{}).(*cache)
+	if !ok {
+		return loader(ctx)
+	}
+
+	if value, cached := memo.get(key); cached {
+		return typed[T](key, value)
+	}
+
+	value, _, err := memo.loads.Do(ctx, key, func(ctx context.Context) (any, error) {
+		if value, cached := memo.get(key); cached {
+			return value, nil
+		}
+
+		value, err := loader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		memo.set(key, value)
+
+		return value, nil
+	})
+	if err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return typed[T](key, value)
+}
+
+func typed[T any](key any, value any) (T, error) {
+	typedValue, ok := value.(T)
+	if !ok && value != nil {
+		return typedValue, fmt.Errorf("%w: %v: %T is not %v", ErrTypeMismatch, key, value, reflect.TypeOf((*T)(nil)).Elem())
+	}
+
+	return typedValue, nil
+}
+
+func (self *cache) get(key any) (any, bool) {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+
+	value, exists := self.entries[key]
+
+	return value, exists
+}
+
+func (self *cache) set(key any, value any) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.entries != nil {
+		self.entries[key] = value
+	}
+}
+
+func (self *cache) discard() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.entries = nil
+}
@@ -0,0 +1,151 @@
+package memo_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+	"github.com/wspowell/context/memo"
+)
+
+type userKey struct {
+	id int
+}
+
+var errLoad = errors.New("load failed")
+
+func Test_Get(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = memo.WithCache(ctx)
+
+	var loads atomic.Int32
+	loader := func(ctx context.Context) (string, error) {
+		loads.Add(1)
+
+		return "alice", nil
+	}
+
+	for index := 0; index < 3; index++ {
+		name, err := memo.Get(ctx, userKey{id: 1}, loader)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", name)
+	}
+	assert.Equal(t, int32(1), loads.Load())
+
+	_, err := memo.Get(ctx, userKey{id: 2}, loader)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), loads.Load())
+
+	_, err = memo.Get(ctx, userKey{id: 1}, func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	assert.ErrorIs(t, err, memo.ErrTypeMismatch)
+}
+
+func Test_Get_localized_goroutines(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = memo.WithCache(ctx)
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context) (int, error) {
+		loads.Add(1)
+		<-release
+
+		return 42, nil
+	}
+
+	results := make([]<-chan error, 10)
+	for index := range results {
+		results[index] = gofunc.Run(ctx, func(ctx context.Context) error {
+			value, err := memo.Get(ctx, userKey{id: 1}, loader)
+			if err == nil && value != 42 {
+				return errLoad
+			}
+
+			return err
+		})
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	for _, result := range results {
+		assert.NoError(t, <-result)
+	}
+	assert.Equal(t, int32(1), loads.Load())
+}
+
+func Test_Get_error_not_cached(t *testing.T) {
+	t.Parallel()
+
+	ctx := memo.WithCache(context.Background())
+
+	var loads atomic.Int32
+	loader := func(ctx context.Context) (string, error) {
+		if loads.Add(1) == 1 {
+			return "", errLoad
+		}
+
+		return "alice", nil
+	}
+
+	_, err := memo.Get(ctx, userKey{id: 1}, loader)
+	assert.ErrorIs(t, err, errLoad)
+
+	name, err := memo.Get(ctx, userKey{id: 1}, loader)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", name)
+}
+
+func Test_Get_without_cache(t *testing.T) {
+	t.Parallel()
+
+	var loads atomic.Int32
+	loader := func(ctx context.Context) (string, error) {
+		loads.Add(1)
+
+		return "alice", nil
+	}
+
+	_, _ = memo.Get(context.Background(), userKey{id: 1}, loader)
+	_, _ = memo.Get(context.Background(), userKey{id: 1}, loader)
+	assert.Equal(t, int32(2), loads.Load())
+}
+
+func Test_Get_discarded_on_cancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = memo.WithCache(ctx)
+
+	var loads atomic.Int32
+	loader := func(ctx context.Context) (string, error) {
+		loads.Add(1)
+
+		return "alice", nil
+	}
+
+	_, err := memo.Get(ctx, userKey{id: 1}, loader)
+	assert.NoError(t, err)
+
+	cancel()
+
+	// Keep the cache but not the cancelation, to observe the discarded cache.
+	detached := context.WithoutCancel(ctx)
+	assert.Eventually(t, func() bool {
+		_, err := memo.Get(detached, userKey{id: 1}, loader)
+
+		return err == nil && loads.Load() > 2
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,199 @@
+// Package metrics records request scoped counters, timers, and histograms in
+// a local value of the Context.
+//
+// Code deep in the call stack records into the Recorder of its goroutine,
+// which is never contended by other goroutines recording. When a goroutine that
+// Localized the Context is joined, see context.Join, its Recorder is merged
+// into the Recorder of the parent goroutine. The aggregate of the whole tree
+// of goroutines is flushed to a Sink when the Context returned by WithRecorder
+// is done.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/wspowell/context"
+)
+
+// Sink receives the aggregated metrics of a Context.
+// Flush may be called concurrently and must be thread safe.
+type Sink interface {
+	Flush(snapshot Snapshot)
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(snapshot Snapshot)
+
+// Flush implements Sink.
+func (self SinkFunc) Flush(snapshot Snapshot) {
+	self(snapshot)
+}
+
+type recorderKey struct{}
+
+// WithRecorder attaches a Recorder to ctx, which must be localized to the
+// current goroutine. The metrics recorded on the returned Context and the
+// goroutines Localized from it are flushed to sink once, when the returned
+// Context is done, either by calling the CancelFunc or by ctx being canceled or
+// expiring. The flush is complete when the CancelFunc returns. Recorders of
+// goroutines joined after the flush are flushed to sink individually.
+//
+// 	ctx, cancel := metrics.WithRecorder(ctx, sink)
+// 	defer cancel()
+func WithRecorder(ctx context.Context, sink Sink) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	root := newRecorder(nil, sink)
+	context.WithLocalValue(ctx, recorderKey{}, root)
+
+	var flushOnce sync.Once
+	flush := func() {
+		flushOnce.Do(root.close)
+	}
+
+	go func() {
+		<-ctx.Done()
+		flush()
+	}()
+
+	return ctx, func() {
+		cancel()
+		flush()
+	}
+}
+
+// Add delta to the counter name.
+func Add(ctx context.Context, name string, delta int64) {
+	if recorder := recorderOf(ctx); recorder != nil {
+		recorder.mutex.Lock()
+		if !recorder.closed {
+			recorder.snapshot.Counters[name] += delta
+		}
+		recorder.mutex.Unlock()
+	}
+}
+
+// Inc increments the counter name.
+func Inc(ctx context.Context, name string) {
+	Add(ctx, name, 1)
+}
+
+// RecordDuration records duration into the timer name.
+func RecordDuration(ctx context.Context, name string, duration time.Duration) {
+	if recorder := recorderOf(ctx); recorder != nil {
+		recorder.mutex.Lock()
+		if !recorder.closed {
+			timer := recorder.snapshot.Timers[name]
+			timer.record(duration)
+			recorder.snapshot.Timers[name] = timer
+		}
+		recorder.mutex.Unlock()
+	}
+}
+
+// Time starts the timer name. The returned function records the elapsed time.
+//
+// 	defer metrics.Time(ctx, "query")()
+func Time(ctx context.Context, name string) func() {
+	start := time.Now()
+
+	return func() {
+		RecordDuration(ctx, name, time.Since(start))
+	}
+}
+
+// Observe records value into the histogram name.
+func Observe(ctx context.Context, name string, value float64) {
+	if recorder := recorderOf(ctx); recorder != nil {
+		recorder.mutex.Lock()
+		if !recorder.closed {
+			histogram, exists := recorder.snapshot.Histograms[name]
+			if !exists {
+				histogram = newHistogram()
+				recorder.snapshot.Histograms[name] = histogram
+			}
+			histogram.observe(value)
+		}
+		recorder.mutex.Unlock()
+	}
+}
+
+func recorderOf(ctx context.Context) *recorder {
+	recorder, _ := ctx.Value(recorderKey{}).(*recorder)
+
+	return recorder
+}
+
+// recorder holds the metrics of a goroutine. The snapshot is recorded into by
+// the owning goroutine. Recorders of joined child goroutines are merged into it
+// and the root recorder may be flushed by another goroutine when its Context
+// is done, so the snapshot is guarded by a mutex that is otherwise uncontended.
+type recorder struct {
+	parent *recorder
+	sink   Sink
+
+	mutex    sync.Mutex
+	snapshot Snapshot
+	closed   bool
+}
+
+func newRecorder(parent *recorder, sink Sink) *recorder {
+	return &recorder{
+		parent:   parent,
+		sink:     sink,
+		snapshot: newSnapshot(),
+	}
+}
+
+// Localize starts an empty Recorder for the new goroutine.
+func (self *recorder) Localize() any {
+	return newRecorder(self, nil)
+}
+
+// Join merges the Recorder of the goroutine into its parent.
+func (self *recorder) Join() {
+	self.close()
+}
+
+// close delivers the snapshot to the parent or sink.
+// Snapshots delivered afterwards are forwarded directly.
+func (self *recorder) close() {
+	self.mutex.Lock()
+	if self.closed {
+		self.mutex.Unlock()
+
+		return
+	}
+	self.closed = true
+	snapshot := self.snapshot
+	self.mutex.Unlock()
+
+	self.forward(snapshot)
+}
+
+// deliver a snapshot of a joined child goroutine.
+func (self *recorder) deliver(snapshot Snapshot) {
+	self.mutex.Lock()
+	if !self.closed {
+		self.snapshot.merge(snapshot)
+		self.mutex.Unlock()
+
+		return
+	}
+	self.mutex.Unlock()
+
+	self.forward(snapshot)
+}
+
+func (self *recorder) forward(snapshot Snapshot) {
+	if snapshot.IsEmpty() {
+		return
+	}
+
+	if self.parent != nil {
+		self.parent.deliver(snapshot)
+	} else if self.sink != nil {
+		self.sink.Flush(snapshot)
+	}
+}
@@ -0,0 +1,162 @@
+package metrics_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+	"github.com/wspowell/context/metrics"
+)
+
+type sink struct {
+	mutex     sync.Mutex
+	snapshots []metrics.Snapshot
+}
+
+func (self *sink) Flush(snapshot metrics.Snapshot) {
+	self.mutex.Lock()
+	self.snapshots = append(self.snapshots, snapshot)
+	self.mutex.Unlock()
+}
+
+func (self *sink) flushed() []metrics.Snapshot {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return append([]metrics.Snapshot(nil), self.snapshots...)
+}
+
+func Test_WithRecorder(t *testing.T) {
+	t.Parallel()
+
+	sink := &sink{}
+
+	ctx, cancel := metrics.WithRecorder(context.Background(), sink)
+
+	metrics.Inc(ctx, "requests")
+	metrics.Add(ctx, "bytes", 512)
+	metrics.RecordDuration(ctx, "query", 2*time.Millisecond)
+	metrics.RecordDuration(ctx, "query", 4*time.Millisecond)
+	metrics.Observe(ctx, "size", 0.2)
+	metrics.Observe(ctx, "size", 20)
+	metrics.Time(ctx, "handler")()
+
+	assert.Empty(t, sink.flushed())
+
+	cancel()
+	cancel()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	snapshots := sink.flushed()
+	assert.Len(t, snapshots, 1)
+
+	snapshot := snapshots[0]
+	assert.Equal(t, map[string]int64{"requests": 1, "bytes": 512}, snapshot.Counters)
+	assert.Equal(t, metrics.Timer{Count: 2, Total: 6 * time.Millisecond, Min: 2 * time.Millisecond, Max: 4 * time.Millisecond}, snapshot.Timers["query"])
+	assert.Equal(t, int64(1), snapshot.Timers["handler"].Count)
+	assert.Equal(t, int64(2), snapshot.Histograms["size"].Count)
+	assert.Equal(t, int64(1), snapshot.Histograms["size"].Counts[5])
+	assert.Equal(t, int64(1), snapshot.Histograms["size"].Counts[len(metrics.DefaultBuckets)])
+
+	// Recording after the flush is ignored.
+	metrics.Inc(ctx, "requests")
+	assert.Len(t, sink.flushed(), 1)
+}
+
+func Test_WithRecorder_join(t *testing.T) {
+	t.Parallel()
+
+	sink := &sink{}
+
+	ctx, cancel := metrics.WithRecorder(context.Background(), sink)
+	metrics.Inc(ctx, "requests")
+
+	errs := make([]<-chan error, 0, 4)
+	for i := 0; i < 4; i++ {
+		errs = append(errs, gofunc.Run(ctx, func(ctx context.Context) error {
+			metrics.Inc(ctx, "workers")
+
+			return <-gofunc.Run(ctx, func(ctx context.Context) error {
+				metrics.Add(ctx, "rows", 10)
+				metrics.RecordDuration(ctx, "query", time.Millisecond)
+
+				return nil
+			})
+		}))
+	}
+	for _, err := range errs {
+		assert.NoError(t, <-err)
+	}
+
+	cancel()
+
+	snapshots := sink.flushed()
+	assert.Len(t, snapshots, 1)
+	assert.Equal(t, map[string]int64{"requests": 1, "workers": 4, "rows": 40}, snapshots[0].Counters)
+	assert.Equal(t, int64(4), snapshots[0].Timers["query"].Count)
+}
+
+func Test_WithRecorder_late_join(t *testing.T) {
+	t.Parallel()
+
+	sink := &sink{}
+
+	ctx, cancel := metrics.WithRecorder(context.Background(), sink)
+
+	start := make(chan struct{})
+	err := gofunc.Run(ctx, func(ctx context.Context) error {
+		<-start
+		metrics.Inc(ctx, "late")
+
+		return nil
+	})
+
+	cancel()
+	close(start)
+	assert.NoError(t, <-err)
+
+	// Nothing was recorded before the flush, the late goroutine is flushed on its own.
+	snapshots := sink.flushed()
+	assert.Len(t, snapshots, 1)
+	assert.Equal(t, map[string]int64{"late": 1}, snapshots[0].Counters)
+}
+
+func Test_WithRecorder_parent_canceled(t *testing.T) {
+	t.Parallel()
+
+	sink := &sink{}
+
+	parent, parentCancel := context.WithCancel(context.Background())
+	ctx, cancel := metrics.WithRecorder(parent, sink)
+	defer cancel()
+
+	metrics.Inc(ctx, "requests")
+
+	parentCancel()
+	<-ctx.Done()
+
+	assert.Eventually(t, func() bool {
+		return len(sink.flushed()) == 1
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	snapshots := sink.flushed()
+	assert.Len(t, snapshots, 1)
+	assert.Equal(t, map[string]int64{"requests": 1}, snapshots[0].Counters)
+}
+
+func Test_no_recorder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	assert.NotPanics(t, func() {
+		metrics.Inc(ctx, "requests")
+		metrics.Observe(ctx, "size", 1)
+		metrics.Time(ctx, "handler")()
+	})
+}
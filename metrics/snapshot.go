@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"time"
+)
+
+// DefaultBuckets are the upper bounds of the buckets of every Histogram.
+// nolint:gochecknoglobals // reason: constant slice
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Timer summarizes recorded durations.
+type Timer struct {
+	Count int64
+	Total time.Duration
+	Min   time.Duration
+	Max   time.Duration
+}
+
+func (self *Timer) record(duration time.Duration) {
+	if self.Count == 0 || duration < self.Min {
+		self.Min = duration
+	}
+	if duration > self.Max {
+		self.Max = duration
+	}
+	self.Count++
+	self.Total += duration
+}
+
+func (self *Timer) merge(other Timer) {
+	if other.Count == 0 {
+		return
+	}
+	if self.Count == 0 || other.Min < self.Min {
+		self.Min = other.Min
+	}
+	if other.Max > self.Max {
+		self.Max = other.Max
+	}
+	self.Count += other.Count
+	self.Total += other.Total
+}
+
+// Histogram counts observed values in buckets.
+// Counts[i] is the number of values at most Bounds[i]; the last count holds
+// the values above every bound.
+type Histogram struct {
+	Bounds []float64
+	Counts []int64
+	Count  int64
+	Sum    float64
+}
+
+func newHistogram() *Histogram {
+	return &Histogram{
+		Bounds: DefaultBuckets,
+		Counts: make([]int64, len(DefaultBuckets)+1),
+	}
+}
+
+func (self *Histogram) observe(value float64) {
+	index := len(self.Bounds)
+	for bucket, bound := range self.Bounds {
+		if value <= bound {
+			index = bucket
+
+			break
+		}
+	}
+
+	self.Counts[index]++
+	self.Count++
+	self.Sum += value
+}
+
+func (self *Histogram) merge(other *Histogram) {
+	for index, count := range other.Counts {
+		self.Counts[index] += count
+	}
+	self.Count += other.Count
+	self.Sum += other.Sum
+}
+
+// Snapshot is the aggregate of the metrics recorded for a Context.
+type Snapshot struct {
+	Counters   map[string]int64
+	Timers     map[string]Timer
+	Histograms map[string]*Histogram
+}
+
+func newSnapshot() Snapshot {
+	return Snapshot{
+		Counters:   map[string]int64{},
+		Timers:     map[string]Timer{},
+		Histograms: map[string]*Histogram{},
+	}
+}
+
+// IsEmpty reports whether nothing was recorded.
+func (self Snapshot) IsEmpty() bool {
+	return len(self.Counters) == 0 && len(self.Timers) == 0 && len(self.Histograms) == 0
+}
+
+func (self Snapshot) merge(other Snapshot) {
+	for name, value := range other.Counters {
+		self.Counters[name] += value
+	}
+
+	for name, other := range other.Timers {
+		timer := self.Timers[name]
+		timer.merge(other)
+		self.Timers[name] = timer
+	}
+
+	for name, other := range other.Histograms {
+		histogram, exists := self.Histograms[name]
+		if !exists {
+			histogram = newHistogram()
+			self.Histograms[name] = histogram
+		}
+		histogram.merge(other)
+	}
+}
@@ -0,0 +1,85 @@
+package context
+
+import (
+	"strings"
+)
+
+// &nameCtxKey is the key that a nameCtx returns itself for.
+// nolint:gochecknoglobals // reason: matches cancelCtxKey
+var nameCtxKey int
+
+// WithName returns a copy of parent with a human readable name attached.
+//
+// Names are used for diagnostics only. They show up in String(), panic
+// messages, deadline errors, and Context trees. Names of nested Contexts form
+// a path, see NamePath.
+//
+// 	ctx = context.WithName(ctx, "checkout")
+// 	ctx = context.WithName(ctx, "charge")
+// 	context.NamePath(ctx) // "checkout/charge"
+func WithName(parent Context, name string) Context {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+
+	return &nameCtx{
+		Context: parent,
+		name:    name,
+	}
+}
+
+// Name returns the name of the closest named Context, or "" if none is named.
+func Name(ctx Context) string {
+	if named, ok := ctx.Value(&nameCtxKey).(*nameCtx); ok {
+		return named.name
+	}
+
+	return ""
+}
+
+// NamePath returns the names of every named Context from the root to ctx
+// joined by "/", or "" if none is named.
+func NamePath(ctx Context) string {
+	var names []string
+	for named, ok := ctx.Value(&nameCtxKey).(*nameCtx); ok; named, ok = named.Context.Value(&nameCtxKey).(*nameCtx) {
+		names = append(names, named.name)
+	}
+
+	if len(names) == 0 {
+		return ""
+	}
+
+	// Names were collected innermost first.
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+
+	return strings.Join(names, "/")
+}
+
+// describe returns a suffix naming ctx for panic and error messages.
+func describe(ctx Context) string {
+	if path := NamePath(ctx); path != "" {
+		return " (" + path + ")"
+	}
+
+	return ""
+}
+
+// A nameCtx carries a name. It delegates all other calls to the embedded Context.
+type nameCtx struct {
+	Context
+	name string
+}
+
+func (c *nameCtx) Value(key any) any {
+	if key == &nameCtxKey {
+		return c
+	}
+
+	return c.Context.Value(key)
+}
+
+func (c *nameCtx) String() string {
+	return contextName(c.Context) + ".WithName(" + c.name + ")"
+}
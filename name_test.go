@@ -0,0 +1,64 @@
+package context_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+)
+
+func Test_WithName(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assert.Equal(t, "", context.Name(ctx))
+	assert.Equal(t, "", context.NamePath(ctx))
+
+	ctx = context.WithName(ctx, "api")
+	ctx = context.WithValue(ctx, immutableContextKey{}, immutableValue)
+	ctx = context.WithName(ctx, "checkout")
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx = context.WithName(ctx, "charge")
+
+	assert.Equal(t, "charge", context.Name(ctx))
+	assert.Equal(t, "api/checkout/charge", context.NamePath(ctx))
+	assert.Equal(t, immutableValue, ctx.Value(immutableContextKey{}))
+	assert.Contains(t, ctx.(interface{ String() string }).String(), ".WithName(checkout).WithCancel.WithName(charge)")
+}
+
+func Test_WithName_deadline_error(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.WithName(context.Background(), "db")
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+
+	child, childCancel := context.WithCancel(ctx)
+	defer childCancel()
+
+	<-child.Done()
+
+	assert.Equal(t, "context deadline exceeded (db)", child.Err().Error())
+	assert.True(t, errors.Is(child.Err(), context.DeadlineExceeded))
+	assert.False(t, errors.Is(child.Err(), context.Canceled))
+}
+
+func Test_WithName_Tree(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.WithName(context.Background(), "api"))
+	defer cancel()
+
+	_, childCancel := context.WithCancel(context.WithName(ctx, "checkout"))
+	defer childCancel()
+
+	node := context.Tree(ctx)
+	assert.Equal(t, "api", node.Name)
+	assert.Len(t, node.Children, 1)
+	assert.Equal(t, "api/checkout", node.Children[0].Name)
+	assert.Contains(t, node.String(), `name="api/checkout"`)
+}
@@ -0,0 +1,196 @@
+// Package pipeline chains typed stages that process values concurrently.
+//
+// A Pipeline starts with a Source, continues with any number of Stages, and
+// ends with Collect. Every Stage has its own number of workers and buffer
+// size, and blocks when the next Stage falls behind. Workers run using
+// gofunc.Run on a Context named after the Stage, so each one has its own
+// local values. The first error cancels the Pipeline, and Collect returns it
+// once every worker has stopped.
+//
+// 	p := pipeline.New(ctx, pipeline.Ordered())
+// 	ids := pipeline.Source(p, slices.Values(userIDs))
+// 	users := pipeline.Stage(ids, "load", loadUser, pipeline.Workers(8))
+// 	names := pipeline.Stage(users, "format", formatName)
+// 	result, err := pipeline.Collect(names)
+package pipeline
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/ctxchan"
+	"github.com/wspowell/context/gofunc"
+)
+
+// LatencyPrefix prefixes the name of the timer of each Stage, see WithLatencyMetrics.
+const LatencyPrefix = "pipeline."
+
+// StageError is the error of a Stage function, or its panic.
+type StageError struct {
+	stage string
+	err   error
+}
+
+// Stage returns the name of the Stage that failed.
+func (self *StageError) Stage() string {
+	return self.stage
+}
+
+func (self *StageError) Error() string {
+	return "stage " + self.stage + ": " + self.err.Error()
+}
+
+func (self *StageError) Unwrap() error {
+	return self.err
+}
+
+// Option configures a Pipeline.
+type Option func(*Pipeline)
+
+// Ordered makes every Stage emit its values in the order of the Source,
+// regardless of which worker finished first.
+func Ordered() Option {
+	return func(pipeline *Pipeline) {
+		pipeline.ordered = true
+	}
+}
+
+// WithLatencyMetrics records the duration of every call of a Stage function
+// into the timer LatencyPrefix + stage name, see metrics.RecordDuration.
+func WithLatencyMetrics() Option {
+	return func(pipeline *Pipeline) {
+		pipeline.latency = true
+	}
+}
+
+// Pipeline of Stages. Every Pipeline must end with Collect, which waits for
+// its workers to stop.
+type Pipeline struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	ordered bool
+	latency bool
+
+	running sync.WaitGroup
+	mutex   sync.Mutex
+	err     error
+}
+
+// New Pipeline. The Pipeline stops when ctx is done.
+func New(ctx context.Context, options ...Option) *Pipeline {
+	pipeline := &Pipeline{}
+	pipeline.ctx, pipeline.cancel = context.WithCancel(ctx)
+
+	for _, option := range options {
+		option(pipeline)
+	}
+
+	return pipeline
+}
+
+type item[T any] struct {
+	seq   uint64
+	value T
+}
+
+// Stream of values flowing between Stages.
+type Stream[T any] struct {
+	pipeline *Pipeline
+	values   <-chan item[T]
+}
+
+// Source starts the Pipeline with the values of seq.
+func Source[T any](pipeline *Pipeline, seq iter.Seq[T]) Stream[T] {
+	values := make(chan item[T])
+
+	pipeline.run("source", 1, func(ctx context.Context) error {
+		var next uint64
+		for value := range seq {
+			if err := ctxchan.Send(ctx, values, item[T]{seq: next, value: value}); err != nil {
+				return err
+			}
+			next++
+		}
+
+		return nil
+	}, func() {
+		close(values)
+	})
+
+	return Stream[T]{
+		pipeline: pipeline,
+		values:   values,
+	}
+}
+
+// Collect the values of stream, waiting until every worker of the Pipeline
+// stopped. Returns the first error of the Pipeline, in which case the values
+// are discarded.
+func Collect[T any](stream Stream[T]) ([]T, error) {
+	var values []T
+	for value := range stream.values {
+		values = append(values, value.value)
+	}
+
+	if err := stream.pipeline.wait(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// run starts workers goroutines running fn. Once all of them returned, done
+// is called.
+func (self *Pipeline) run(name string, workers int, fn gofunc.RunFn, done func()) {
+	ctx := context.WithName(self.ctx, name)
+
+	var running sync.WaitGroup
+	running.Add(workers)
+	for index := 0; index < workers; index++ {
+		result := gofunc.Run(ctx, fn)
+		go func() {
+			defer running.Done()
+
+			if err := <-result; err != nil {
+				var panicErr *gofunc.PanicError
+				if errors.As(err, &panicErr) {
+					err = &StageError{stage: name, err: err}
+				}
+				self.fail(err)
+			}
+		}()
+	}
+
+	self.running.Add(1)
+	go func() {
+		defer self.running.Done()
+
+		running.Wait()
+		done()
+	}()
+}
+
+// fail the Pipeline with err, unless it already failed.
+func (self *Pipeline) fail(err error) {
+	self.mutex.Lock()
+	if self.err == nil {
+		self.err = err
+	}
+	self.mutex.Unlock()
+
+	self.cancel()
+}
+
+func (self *Pipeline) wait() error {
+	self.running.Wait()
+	self.cancel()
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return self.err
+}
@@ -0,0 +1,207 @@
+package pipeline_test
+
+import (
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/metrics"
+	"github.com/wspowell/context/pipeline"
+)
+
+var errStage = errors.New("stage failed")
+
+type localKey struct{}
+
+func double(ctx context.Context, value int) (int, error) {
+	return value * 2, nil
+}
+
+func format(ctx context.Context, value int) (string, error) {
+	return strconv.Itoa(value), nil
+}
+
+func Test_Pipeline(t *testing.T) {
+	t.Parallel()
+
+	p := pipeline.New(context.Background())
+	doubled := pipeline.Stage(pipeline.Source(p, slices.Values([]int{1, 2, 3})), "double", double, pipeline.Workers(3), pipeline.Buffer(2))
+	formatted := pipeline.Stage(doubled, "format", format)
+
+	values, err := pipeline.Collect(formatted)
+	assert.NoError(t, err)
+	slices.Sort(values)
+	assert.Equal(t, []string{"2", "4", "6"}, values)
+}
+
+func Test_Pipeline_Ordered(t *testing.T) {
+	t.Parallel()
+
+	input := make([]int, 100)
+	for index := range input {
+		input[index] = index
+	}
+
+	p := pipeline.New(context.Background(), pipeline.Ordered())
+	slow := pipeline.Stage(pipeline.Source(p, slices.Values(input)), "jitter", func(ctx context.Context, value int) (int, error) {
+		time.Sleep(time.Duration(value%7) * time.Millisecond)
+
+		return value, nil
+	}, pipeline.Workers(8))
+
+	values, err := pipeline.Collect(slow)
+	assert.NoError(t, err)
+	assert.Equal(t, input, values)
+}
+
+func Test_Pipeline_Ordered_stalled(t *testing.T) {
+	t.Parallel()
+
+	const (
+		workers = 4
+		buffer  = 2
+	)
+
+	input := make([]int, 1000)
+	for index := range input {
+		input[index] = index
+	}
+
+	var (
+		started atomic.Int32
+		release = make(chan struct{})
+	)
+
+	p := pipeline.New(context.Background(), pipeline.Ordered())
+	stalled := pipeline.Stage(pipeline.Source(p, slices.Values(input)), "stalled", func(ctx context.Context, value int) (int, error) {
+		started.Add(1)
+		if value == 0 {
+			<-release
+		}
+
+		return value, nil
+	}, pipeline.Workers(workers), pipeline.Buffer(buffer))
+
+	done := make(chan []int)
+	go func() {
+		values, err := pipeline.Collect(stalled)
+		assert.NoError(t, err)
+		done <- values
+	}()
+
+	// Values after the stalled first value are held back by the reserved slots.
+	time.Sleep(20 * time.Millisecond)
+	assert.LessOrEqual(t, started.Load(), int32(workers+buffer))
+
+	close(release)
+	assert.Equal(t, input, <-done)
+}
+
+func Test_Pipeline_error(t *testing.T) {
+	t.Parallel()
+
+	var processed atomic.Int32
+
+	p := pipeline.New(context.Background())
+	failing := pipeline.Stage(pipeline.Source(p, func(yield func(int) bool) {
+		for value := 0; ; value++ {
+			if !yield(value) {
+				return
+			}
+		}
+	}), "fail", func(ctx context.Context, value int) (int, error) {
+		processed.Add(1)
+		if value == 10 {
+			return 0, errStage
+		}
+
+		return value, nil
+	}, pipeline.Workers(4))
+	after := pipeline.Stage(failing, "after", double)
+
+	values, err := pipeline.Collect(after)
+	assert.Nil(t, values)
+	assert.ErrorIs(t, err, errStage)
+
+	var stageErr *pipeline.StageError
+	assert.True(t, errors.As(err, &stageErr))
+	assert.Equal(t, "fail", stageErr.Stage())
+	assert.Equal(t, "stage fail: stage failed", err.Error())
+	assert.Less(t, processed.Load(), int32(100))
+}
+
+func Test_Pipeline_panic(t *testing.T) {
+	t.Parallel()
+
+	p := pipeline.New(context.Background())
+	panicking := pipeline.Stage(pipeline.Source(p, slices.Values([]int{1})), "panic", func(ctx context.Context, value int) (int, error) {
+		panic("boom")
+	})
+
+	_, err := pipeline.Collect(panicking)
+	assert.ErrorIs(t, err, errors.ErrPanic)
+
+	var stageErr *pipeline.StageError
+	assert.True(t, errors.As(err, &stageErr))
+	assert.Equal(t, "panic", stageErr.Stage())
+}
+
+func Test_Pipeline_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := pipeline.New(ctx)
+	blocked := pipeline.Stage(pipeline.Source(p, slices.Values([]int{1, 2, 3})), "block", func(ctx context.Context, value int) (int, error) {
+		cancel()
+		<-ctx.Done()
+
+		return value, nil
+	})
+
+	_, err := pipeline.Collect(blocked)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_Pipeline_localized_workers(t *testing.T) {
+	t.Parallel()
+
+	p := pipeline.New(context.Background())
+	local := pipeline.Stage(pipeline.Source(p, slices.Values([]int{1, 2, 3, 4})), "local", func(ctx context.Context, value int) (int, error) {
+		count, _ := ctx.Value(localKey{}).(int)
+		context.WithLocalValue(ctx, localKey{}, count+1)
+
+		return count, nil
+	}, pipeline.Workers(2))
+
+	values, err := pipeline.Collect(local)
+	assert.NoError(t, err)
+	assert.Len(t, values, 4)
+	for _, value := range values {
+		// Each worker counts only the values it processed.
+		assert.Less(t, value, 4)
+	}
+}
+
+func Test_Pipeline_latency_metrics(t *testing.T) {
+	t.Parallel()
+
+	var snapshot metrics.Snapshot
+
+	ctx, cancel := metrics.WithRecorder(context.Background(), metrics.SinkFunc(func(flushed metrics.Snapshot) {
+		snapshot = flushed
+	}))
+
+	p := pipeline.New(ctx, pipeline.WithLatencyMetrics())
+	_, err := pipeline.Collect(pipeline.Stage(pipeline.Source(p, slices.Values([]int{1, 2, 3})), "double", double, pipeline.Workers(2)))
+	assert.NoError(t, err)
+
+	cancel()
+	assert.Equal(t, int64(3), snapshot.Timers[pipeline.LatencyPrefix+"double"].Count)
+}
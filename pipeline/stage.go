@@ -0,0 +1,138 @@
+package pipeline
+
+import (
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/ctxchan"
+	"github.com/wspowell/context/metrics"
+)
+
+// StageFunc transforms one value of a Stage.
+type StageFunc[In any, Out any] func(ctx context.Context, value In) (Out, error)
+
+// StageOption configures a Stage.
+type StageOption func(*stageConfig)
+
+type stageConfig struct {
+	workers int
+	buffer  int
+}
+
+// Workers sets the number of goroutines running the Stage. Defaults to 1.
+func Workers(workers int) StageOption {
+	return func(config *stageConfig) {
+		if workers > 0 {
+			config.workers = workers
+		}
+	}
+}
+
+// Buffer sets the number of values the Stage emits before blocking on the
+// next Stage. Defaults to 0.
+func Buffer(size int) StageOption {
+	return func(config *stageConfig) {
+		if size > 0 {
+			config.buffer = size
+		}
+	}
+}
+
+// Stage calls fn for every value of from and streams the results.
+// An error returned by fn fails the Pipeline with a *StageError.
+func Stage[In any, Out any](from Stream[In], name string, fn StageFunc[In, Out], options ...StageOption) Stream[Out] {
+	config := stageConfig{
+		workers: 1,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	pipeline := from.pipeline
+	values := make(chan item[Out], config.buffer)
+
+	var (
+		output chan item[Out]
+		// slots bounds the values taken by workers and not yet emitted in order.
+		slots chan struct{}
+	)
+	if pipeline.ordered && config.workers > 1 {
+		// Workers race each other, restore the order before emitting.
+		output = make(chan item[Out], config.workers)
+		slots = make(chan struct{}, config.workers+config.buffer)
+		pipeline.run(name+"/order", 1, func(ctx context.Context) error {
+			return reorder(ctx, output, values, slots)
+		}, func() {
+			close(values)
+		})
+	} else {
+		output = values
+	}
+
+	pipeline.run(name, config.workers, func(ctx context.Context) error {
+		for {
+			// Reserve a slot before taking a value so that a stalled value
+			// stops the workers instead of growing the reorder buffer. Values
+			// are taken in sequence order, so the next value to emit always
+			// holds a slot.
+			if slots != nil {
+				if err := ctxchan.Send(ctx, slots, struct{}{}); err != nil {
+					return err
+				}
+			}
+
+			input, err := ctxchan.Recv(ctx, from.values)
+			if errors.Is(err, ctxchan.ErrClosed) {
+				return ctx.Err()
+			} else if err != nil {
+				return err
+			}
+
+			var stop func()
+			if pipeline.latency {
+				stop = metrics.Time(ctx, LatencyPrefix+name)
+			}
+
+			value, err := fn(ctx, input.value)
+			if stop != nil {
+				stop()
+			}
+			if err != nil {
+				return &StageError{stage: name, err: err}
+			}
+
+			if err := ctxchan.Send(ctx, output, item[Out]{seq: input.seq, value: value}); err != nil {
+				return err
+			}
+		}
+	}, func() {
+		close(output)
+	})
+
+	return Stream[Out]{
+		pipeline: pipeline,
+		values:   values,
+	}
+}
+
+// reorder emits the items of input in sequence order, releasing the slot of
+// every item emitted.
+func reorder[T any](ctx context.Context, input <-chan item[T], output chan<- item[T], slots <-chan struct{}) error {
+	var next uint64
+	pending := map[uint64]T{}
+
+	for received := range ctxchan.Range(ctx, input) {
+		pending[received.seq] = received.value
+
+		for value, ok := pending[next]; ok; value, ok = pending[next] {
+			delete(pending, next)
+			if err := ctxchan.Send(ctx, output, item[T]{seq: next, value: value}); err != nil {
+				return err
+			}
+			<-slots
+			next++
+		}
+	}
+
+	return ctx.Err()
+}
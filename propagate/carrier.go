@@ -0,0 +1,79 @@
+package propagate
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Carrier stores the wire representation of propagated values.
+type Carrier interface {
+	// Get the value stored under name, or "" if there is none.
+	Get(name string) string
+	// Set the value stored under name.
+	Set(name string, value string)
+}
+
+// HeaderCarrier carries values in HTTP headers, using the registered names as
+// header keys.
+type HeaderCarrier http.Header
+
+func (self HeaderCarrier) Get(name string) string {
+	return http.Header(self).Get(name)
+}
+
+func (self HeaderCarrier) Set(name string, value string) {
+	http.Header(self).Set(name, value)
+}
+
+// MapCarrier carries values in a map, using the registered names as keys.
+type MapCarrier map[string]string
+
+func (self MapCarrier) Get(name string) string {
+	return self[name]
+}
+
+func (self MapCarrier) Set(name string, value string) {
+	self[name] = value
+}
+
+// EnvCarrier carries values in environment variables formatted as
+// "KEY=value", such as os.Environ() or exec.Cmd.Env.
+//
+// Registered names are converted to environment variable names by upper
+// casing them and replacing '-' and '.' with '_', prefixed by Prefix.
+type EnvCarrier struct {
+	Prefix string
+	Env    []string
+}
+
+func (self *EnvCarrier) Get(name string) string {
+	prefix := self.variable(name) + "="
+	for index := len(self.Env) - 1; index >= 0; index-- {
+		if strings.HasPrefix(self.Env[index], prefix) {
+			return self.Env[index][len(prefix):]
+		}
+	}
+
+	return ""
+}
+
+func (self *EnvCarrier) Set(name string, value string) {
+	variable := self.variable(name)
+	prefix := variable + "="
+	for index := range self.Env {
+		if strings.HasPrefix(self.Env[index], prefix) {
+			self.Env[index] = variable + "=" + value
+
+			return
+		}
+	}
+
+	self.Env = append(self.Env, variable+"="+value)
+}
+
+// nolint:gochecknoglobals // reason: constant replacer
+var envReplacer = strings.NewReplacer("-", "_", ".", "_")
+
+func (self *EnvCarrier) variable(name string) string {
+	return self.Prefix + strings.ToUpper(envReplacer.Replace(name))
+}
@@ -0,0 +1,129 @@
+package propagate
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Codec converts values to and from their wire representation.
+type Codec[V any] interface {
+	Encode(value V) (string, error)
+	Decode(data string) (V, error)
+}
+
+// String returns a Codec for values whose underlying type is string.
+func String[V ~string]() Codec[V] {
+	return stringCodec[V]{}
+}
+
+type stringCodec[V ~string] struct{}
+
+func (stringCodec[V]) Encode(value V) (string, error) {
+	return string(value), nil
+}
+
+func (stringCodec[V]) Decode(data string) (V, error) {
+	return V(data), nil
+}
+
+// JSON returns a Codec that encodes values as JSON.
+func JSON[V any]() Codec[V] {
+	return jsonCodec[V]{}
+}
+
+type jsonCodec[V any] struct{}
+
+func (jsonCodec[V]) Encode(value V) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err // nolint:wrapcheck // reason: passthrough of encoding error
+	}
+
+	return string(data), nil
+}
+
+func (jsonCodec[V]) Decode(data string) (V, error) {
+	var value V
+	err := json.Unmarshal([]byte(data), &value)
+
+	return value, err // nolint:wrapcheck // reason: passthrough of decoding error
+}
+
+// Binary returns a Codec that encodes values using their BinaryMarshaler as
+// unpadded, URL safe base64 so that it is safe to use in headers and
+// environment variables.
+func Binary[V encoding.BinaryMarshaler, P interface {
+	*V
+	encoding.BinaryUnmarshaler
+}]() Codec[V] {
+	return binaryCodec[V, P]{}
+}
+
+type binaryCodec[V encoding.BinaryMarshaler, P interface {
+	*V
+	encoding.BinaryUnmarshaler
+}] struct{}
+
+func (binaryCodec[V, P]) Encode(value V) (string, error) {
+	data, err := value.MarshalBinary()
+	if err != nil {
+		return "", err // nolint:wrapcheck // reason: passthrough of encoding error
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func (binaryCodec[V, P]) Decode(data string) (V, error) {
+	var value V
+
+	decoded, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return value, err // nolint:wrapcheck // reason: passthrough of decoding error
+	}
+
+	err = P(&value).UnmarshalBinary(decoded)
+
+	return value, err // nolint:wrapcheck // reason: passthrough of decoding error
+}
+
+// Func returns a Codec using the given functions.
+func Func[V any](encode func(value V) (string, error), decode func(data string) (V, error)) Codec[V] {
+	return funcCodec[V]{
+		encode: encode,
+		decode: decode,
+	}
+}
+
+type funcCodec[V any] struct {
+	encode func(value V) (string, error)
+	decode func(data string) (V, error)
+}
+
+func (self funcCodec[V]) Encode(value V) (string, error) {
+	return self.encode(value)
+}
+
+func (self funcCodec[V]) Decode(data string) (V, error) {
+	return self.decode(data)
+}
+
+// Requires returns codec, restricted to propagate its values only along with
+// the value registered under name. Values are neither injected nor extracted
+// when the value registered under name is missing or fails to encode or
+// decode, such as a W3C tracestate without a valid traceparent.
+func Requires[V any](name string, codec Codec[V]) Codec[V] {
+	return requiresCodec[V]{
+		Codec: codec,
+		name:  name,
+	}
+}
+
+type requiresCodec[V any] struct {
+	Codec[V]
+	name string
+}
+
+func (self requiresCodec[V]) required() string {
+	return self.name
+}
@@ -0,0 +1,210 @@
+// Package propagate serializes immutable Context values so that they can be
+// passed across process boundaries.
+//
+// A key used with context.WithValue is registered under a wire name with a
+// Codec. Inject writes the registered values of a Context into a Carrier and
+// Extract restores them from a Carrier into a Context.
+//
+// Only immutable values are propagated. Local values set using
+// context.WithLocalValue are never read, see context.ImmutableValue.
+package propagate
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+)
+
+var (
+	// ErrLocalValue is returned when registering a value type meant to be local to a goroutine.
+	ErrLocalValue = errors.New("local values cannot be propagated")
+	// ErrDuplicate is returned when registering a name or key twice.
+	ErrDuplicate = errors.New("name or key already registered")
+	// ErrEncode is returned when a value fails to encode.
+	ErrEncode = errors.New("failed to encode value")
+	// ErrDecode is returned when a value fails to decode.
+	ErrDecode = errors.New("failed to decode value")
+)
+
+type registration struct {
+	name string
+	key  any
+	// requires is the name of the registration this one is propagated along
+	// with, see Requires.
+	requires string
+	encode   func(value any) (string, error)
+	decode   func(ctx context.Context, data string) (context.Context, error)
+}
+
+// nolint:gochecknoglobals // reason: registry of propagated keys
+var (
+	registryMutex sync.RWMutex
+	registry      []registration
+)
+
+// nolint:gochecknoglobals // reason: constant type
+var localizerType = reflect.TypeOf((*interface{ Localize() any })(nil)).Elem()
+
+// Register key so that its immutable value is propagated under name using codec.
+//
+// Value types implementing Localize() any, directly or through a pointer, are
+// meant to be used as local values and are rejected with ErrLocalValue. Registering a name or key twice is
+// rejected with ErrDuplicate.
+func Register[K comparable, V any](name string, key K, codec Codec[V]) error {
+	valueType := reflect.TypeOf((*V)(nil)).Elem()
+	if valueType.Implements(localizerType) || reflect.PointerTo(valueType).Implements(localizerType) {
+		return fmt.Errorf("%w: %s", ErrLocalValue, name)
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	for _, registered := range registry {
+		if registered.name == name || registered.key == any(key) {
+			return fmt.Errorf("%w: %s", ErrDuplicate, name)
+		}
+	}
+
+	var requires string
+	if dependent, ok := any(codec).(interface{ required() string }); ok {
+		requires = dependent.required()
+	}
+
+	registry = append(registry, registration{
+		name:     name,
+		key:      key,
+		requires: requires,
+		encode: func(value any) (string, error) {
+			typed, ok := value.(V)
+			if !ok {
+				return "", fmt.Errorf("%w: %s: unexpected type %T", ErrEncode, name, value)
+			}
+
+			data, err := codec.Encode(typed)
+			if err != nil {
+				return "", fmt.Errorf("%w: %s: %w", ErrEncode, name, err)
+			}
+
+			return data, nil
+		},
+		decode: func(ctx context.Context, data string) (context.Context, error) {
+			value, err := codec.Decode(data)
+			if err != nil {
+				return ctx, fmt.Errorf("%w: %s: %w", ErrDecode, name, err)
+			}
+
+			return context.WithValue(ctx, key, value), nil
+		},
+	})
+
+	return nil
+}
+
+// Unregister the key registered under name. Returns false if name is not registered.
+func Unregister(name string) bool {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	for index, registered := range registry {
+		if registered.name == name {
+			registry = append(registry[:index:index], registry[index+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// registrations returns the registered keys, those using Requires last so
+// that the values they require are propagated first.
+func registrations() []registration {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	ordered := make([]registration, 0, len(registry))
+	for _, registered := range registry {
+		if registered.requires == "" {
+			ordered = append(ordered, registered)
+		}
+	}
+	for _, registered := range registry {
+		if registered.requires != "" {
+			ordered = append(ordered, registered)
+		}
+	}
+
+	return ordered
+}
+
+// Inject the registered immutable values of ctx into carrier.
+//
+// Every value is attempted. The first encoding error is returned.
+func Inject(ctx context.Context, carrier Carrier) error {
+	var firstErr error
+	injected := map[string]bool{}
+
+	for _, registered := range registrations() {
+		if registered.requires != "" && !injected[registered.requires] {
+			continue
+		}
+
+		value := context.ImmutableValue(ctx, registered.key)
+		if value == nil {
+			continue
+		}
+
+		data, err := registered.encode(value)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			continue
+		}
+
+		carrier.Set(registered.name, data)
+		injected[registered.name] = true
+	}
+
+	return firstErr
+}
+
+// Extract the registered values found in carrier into a Context derived from ctx.
+//
+// Every value is attempted. Values that fail to decode are skipped and the
+// first decoding error is returned along with the Context of the values that
+// did decode.
+func Extract(ctx context.Context, carrier Carrier) (context.Context, error) {
+	var firstErr error
+	extracted := map[string]bool{}
+
+	for _, registered := range registrations() {
+		if registered.requires != "" && !extracted[registered.requires] {
+			continue
+		}
+
+		data := carrier.Get(registered.name)
+		if data == "" {
+			continue
+		}
+
+		decoded, err := registered.decode(ctx, data)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			continue
+		}
+
+		ctx = decoded
+		extracted[registered.name] = true
+	}
+
+	return ctx, firstErr
+}
@@ -0,0 +1,220 @@
+package propagate_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/propagate"
+)
+
+type tenantKey struct{}
+
+type tenant string
+
+type userKey struct{}
+
+type user struct {
+	ID    int    `json:"id"`
+	Admin bool   `json:"admin"`
+	Name  string `json:"name"`
+}
+
+type versionKey struct{}
+
+type version uint32
+
+func (self version) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, uint32(self))
+
+	return data, nil
+}
+
+func (self *version) UnmarshalBinary(data []byte) error {
+	if len(data) != 4 {
+		return errors.New("invalid version")
+	}
+	*self = version(binary.BigEndian.Uint32(data))
+
+	return nil
+}
+
+type bufferKey struct{}
+
+type buffer struct{}
+
+func (self *buffer) Localize() any {
+	return &buffer{}
+}
+
+// nolint:gochecknoinits // reason: registers propagated test keys once
+func init() {
+	for _, err := range []error{
+		propagate.Register("Tenant", tenantKey{}, propagate.String[tenant]()),
+		propagate.Register("User", userKey{}, propagate.JSON[user]()),
+		propagate.Register("Version", versionKey{}, propagate.Binary[version]()),
+	} {
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func newContext() context.Context {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, tenantKey{}, tenant("acme"))
+	ctx = context.WithValue(ctx, userKey{}, user{ID: 7, Admin: true, Name: "Ada"})
+	ctx = context.WithValue(ctx, versionKey{}, version(3))
+
+	return ctx
+}
+
+func checkContext(t *testing.T, ctx context.Context) {
+	t.Helper()
+
+	assert.Equal(t, tenant("acme"), ctx.Value(tenantKey{}))
+	assert.Equal(t, user{ID: 7, Admin: true, Name: "Ada"}, ctx.Value(userKey{}))
+	assert.Equal(t, version(3), ctx.Value(versionKey{}))
+}
+
+func Test_Inject_Extract(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		about   string
+		carrier func() propagate.Carrier
+	}{
+		{
+			about:   "map",
+			carrier: func() propagate.Carrier { return propagate.MapCarrier{} },
+		},
+		{
+			about:   "headers",
+			carrier: func() propagate.Carrier { return propagate.HeaderCarrier(http.Header{}) },
+		},
+		{
+			about:   "env",
+			carrier: func() propagate.Carrier { return &propagate.EnvCarrier{Prefix: "CTX_", Env: []string{"PATH=/bin"}} },
+		},
+	}
+
+	for index := range testCases {
+		testCase := testCases[index]
+		t.Run(testCase.about, func(t *testing.T) {
+			t.Parallel()
+
+			carrier := testCase.carrier()
+			assert.Nil(t, propagate.Inject(newContext(), carrier))
+
+			ctx, err := propagate.Extract(context.TODO(), carrier)
+			assert.Nil(t, err)
+			checkContext(t, ctx)
+		})
+	}
+}
+
+func Test_Inject_formats(t *testing.T) {
+	t.Parallel()
+
+	carrier := propagate.MapCarrier{}
+	assert.Nil(t, propagate.Inject(newContext(), carrier))
+
+	assert.Equal(t, "acme", carrier["Tenant"])
+	assert.Equal(t, `{"id":7,"admin":true,"name":"Ada"}`, carrier["User"])
+	assert.Equal(t, "AAAAAw", carrier["Version"])
+
+	env := &propagate.EnvCarrier{Prefix: "CTX_"}
+	assert.Nil(t, propagate.Inject(newContext(), env))
+	assert.Contains(t, env.Env, "CTX_TENANT=acme")
+}
+
+func Test_Inject_skips_locals(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	context.WithLocalValue(ctx, tenantKey{}, tenant("local"))
+
+	carrier := propagate.MapCarrier{}
+	assert.Nil(t, propagate.Inject(ctx, carrier))
+	assert.Empty(t, carrier)
+}
+
+func Test_Extract_malformed(t *testing.T) {
+	t.Parallel()
+
+	ctx, err := propagate.Extract(context.TODO(), propagate.MapCarrier{
+		"Tenant":  "acme",
+		"User":    "{not json",
+		"Version": "AA",
+	})
+	assert.True(t, errors.Is(err, propagate.ErrDecode))
+	var syntaxErr *json.SyntaxError
+	assert.True(t, errors.As(err, &syntaxErr))
+	assert.Equal(t, tenant("acme"), ctx.Value(tenantKey{}))
+	assert.Nil(t, ctx.Value(userKey{}))
+	assert.Nil(t, ctx.Value(versionKey{}))
+}
+
+func Test_Register(t *testing.T) {
+	t.Parallel()
+
+	err := propagate.Register("Buffer", bufferKey{}, propagate.JSON[*buffer]())
+	assert.True(t, errors.Is(err, propagate.ErrLocalValue))
+
+	// Localize implemented on the pointer of the value type.
+	err = propagate.Register("BufferValue", bufferKey{}, propagate.JSON[buffer]())
+	assert.True(t, errors.Is(err, propagate.ErrLocalValue))
+
+	err = propagate.Register("Tenant", struct{}{}, propagate.String[string]())
+	assert.True(t, errors.Is(err, propagate.ErrDuplicate))
+
+	err = propagate.Register("Other", tenantKey{}, propagate.String[tenant]())
+	assert.True(t, errors.Is(err, propagate.ErrDuplicate))
+
+	type funcKey struct{}
+	errCannotEncode := errors.New("cannot encode")
+	assert.Nil(t, propagate.Register("Func", funcKey{}, propagate.Func(
+		func(value int) (string, error) { return "", errCannotEncode },
+		func(data string) (int, error) { return 0, nil },
+	)))
+	defer propagate.Unregister("Func")
+
+	err = propagate.Inject(context.WithValue(context.TODO(), funcKey{}, 1), propagate.MapCarrier{})
+	assert.True(t, errors.Is(err, propagate.ErrEncode))
+	assert.True(t, errors.Is(err, errCannotEncode))
+
+	assert.True(t, propagate.Unregister("Func"))
+	assert.False(t, propagate.Unregister("Func"))
+}
+
+func Test_Requires(t *testing.T) {
+	t.Parallel()
+
+	type sessionKey struct{}
+	assert.Nil(t, propagate.Register("Session", sessionKey{}, propagate.Requires("Tenant", propagate.String[string]())))
+	defer propagate.Unregister("Session")
+
+	ctx := context.WithValue(context.WithValue(context.Background(), tenantKey{}, tenant("acme")), sessionKey{}, "s1")
+	carrier := propagate.MapCarrier{}
+	assert.Nil(t, propagate.Inject(ctx, carrier))
+	assert.Equal(t, "s1", carrier["Session"])
+
+	extracted, err := propagate.Extract(context.Background(), carrier)
+	assert.Nil(t, err)
+	assert.Equal(t, "s1", extracted.Value(sessionKey{}))
+
+	// Without the required value.
+	carrier = propagate.MapCarrier{}
+	assert.Nil(t, propagate.Inject(context.WithValue(context.Background(), sessionKey{}, "s1"), carrier))
+	assert.Empty(t, carrier)
+
+	extracted, err = propagate.Extract(context.Background(), propagate.MapCarrier{"Session": "s1"})
+	assert.Nil(t, err)
+	assert.Nil(t, extracted.Value(sessionKey{}))
+}
@@ -0,0 +1,90 @@
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff computes the delay before the next attempt.
+type Backoff interface {
+	// Delay after the failed attempt, numbered from 1. previous is the delay
+	// before the failed attempt, or 0 after the first attempt.
+	Delay(attempt int, previous time.Duration) time.Duration
+}
+
+// Constant waits the same delay between attempts.
+type Constant struct {
+	Interval time.Duration
+}
+
+// Delay implements Backoff.
+func (self Constant) Delay(attempt int, previous time.Duration) time.Duration {
+	return self.Interval
+}
+
+// Exponential multiplies the delay by Multiplier after every attempt, starting
+// at Initial, up to Max. Without Max, the delay is capped at math.MaxInt64.
+type Exponential struct {
+	Initial time.Duration
+	Max     time.Duration
+	// Multiplier defaults to 2.
+	Multiplier float64
+}
+
+// Delay implements Backoff.
+func (self Exponential) Delay(attempt int, previous time.Duration) time.Duration {
+	multiplier := self.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	maxDelay := self.Max
+	if maxDelay <= 0 {
+		maxDelay = math.MaxInt64
+	}
+
+	// Compared as floats since the delay may overflow time.Duration.
+	delay := float64(self.Initial) * math.Pow(multiplier, float64(attempt-1))
+	if delay >= float64(maxDelay) {
+		return maxDelay
+	}
+
+	return time.Duration(delay)
+}
+
+// DecorrelatedJitter picks a random delay between Base and three times the
+// previous delay, up to Max, spreading out the attempts of concurrent callers.
+// Without Max, the delay is capped at math.MaxInt64.
+//
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+type DecorrelatedJitter struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay implements Backoff.
+func (self DecorrelatedJitter) Delay(attempt int, previous time.Duration) time.Duration {
+	if previous < self.Base {
+		previous = self.Base
+	}
+
+	maxDelay := self.Max
+	if maxDelay <= 0 {
+		maxDelay = math.MaxInt64
+	}
+	if previous > maxDelay/3 {
+		// 3*previous would overflow, or exceed the max anyway.
+		previous = maxDelay / 3
+	}
+
+	delay := self.Base
+	if spread := 3*previous - self.Base; spread > 0 {
+		delay += time.Duration(rand.Int63n(int64(spread))) // nolint:gosec // reason: jitter does not need a secure source
+	}
+	if delay > maxDelay {
+		return maxDelay
+	}
+
+	return delay
+}
@@ -0,0 +1,225 @@
+// Package retry runs functions again when they fail, within the deadline of
+// the Context.
+package retry
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+)
+
+var (
+	// ErrBudgetExhausted is returned when the remaining deadline of the Context
+	// is too short for another attempt.
+	ErrBudgetExhausted = errors.New("deadline budget exhausted")
+	// ErrAttemptTimeout matches errors of attempts that exceeded
+	// Policy.AttemptTimeout while the Context of Do was still alive.
+	ErrAttemptTimeout = errors.New("attempt timed out")
+)
+
+// Classifier reports whether err is retryable.
+//
+// Attempts that exceed Policy.AttemptTimeout fail with an error matching both
+// ErrAttemptTimeout and context.DeadlineExceeded. Errors caused by the
+// Context of Do itself are never passed to the Classifier.
+type Classifier func(err error) bool
+
+// DefaultClassifier retries every error except those marked Permanent.
+func DefaultClassifier(err error) bool {
+	var permanent *permanentError
+
+	return !errors.As(err, &permanent)
+}
+
+// Permanent marks err as not retryable for DefaultClassifier.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (self *permanentError) Error() string {
+	return self.err.Error()
+}
+
+func (self *permanentError) Unwrap() error {
+	return self.err
+}
+
+// attemptTimeoutError is the error of an attempt that exceeded Policy.AttemptTimeout.
+type attemptTimeoutError struct {
+	err error
+}
+
+func (self *attemptTimeoutError) Error() string {
+	return ErrAttemptTimeout.Error() + ": " + self.err.Error()
+}
+
+func (self *attemptTimeoutError) Is(target error) bool {
+	return target == ErrAttemptTimeout // nolint:errorlint,goerr113 // reason: sentinel comparison
+}
+
+func (self *attemptTimeoutError) Unwrap() error {
+	return self.err
+}
+
+// Policy configures Do.
+type Policy struct {
+	// MaxAttempts limits the attempts, including the first.
+	// Zero means no limit other than the Context.
+	MaxAttempts int
+	// Backoff between attempts. Defaults to Exponential from 100ms up to 10s.
+	Backoff Backoff
+	// AttemptTimeout bounds each attempt using context.WithTimeout, if set.
+	AttemptTimeout time.Duration
+	// ExpectedAttemptTime is the least remaining deadline budget needed to
+	// start an attempt. Defaults to AttemptTimeout.
+	ExpectedAttemptTime time.Duration
+	// Classifier decides whether a failed attempt is retried.
+	// Defaults to DefaultClassifier.
+	Classifier Classifier
+}
+
+type attemptKey struct{}
+
+// attemptCounter is the local value holding the current attempt of Do on a
+// goroutine. It is set once per goroutine so that nested calls to Do restore
+// the attempt of the enclosing Do, or 0, without leaving a stale value behind.
+type attemptCounter struct {
+	attempt int
+}
+
+// Localize starts new goroutines outside of any attempt.
+func (self *attemptCounter) Localize() any {
+	return &attemptCounter{}
+}
+
+// Attempt returns the number of the current attempt of Do, starting at 1,
+// or 0 outside of Do.
+func Attempt(ctx context.Context) int {
+	if counter, ok := ctx.Value(attemptKey{}).(*attemptCounter); ok {
+		return counter.attempt
+	}
+
+	return 0
+}
+
+// Do calls fn until it succeeds, the Classifier rejects its error, the
+// attempts are exhausted, or ctx is done.
+//
+// An attempt is not started if the remaining deadline budget of ctx is less
+// than Policy.ExpectedAttemptTime, in which case the returned error matches
+// ErrBudgetExhausted as well as the error of the last attempt. If ctx is done,
+// its error is returned. Otherwise, the error of the last attempt is returned.
+//
+// fn runs on the current goroutine, so ctx must be localized to it. The number
+// of the attempt is a local value, see Attempt.
+func Do(ctx context.Context, fn func(ctx context.Context) error, policy Policy) error {
+	backoff := policy.Backoff
+	if backoff == nil {
+		backoff = Exponential{
+			Initial: 100 * time.Millisecond,
+			Max:     10 * time.Second,
+		}
+	}
+	classifier := policy.Classifier
+	if classifier == nil {
+		classifier = DefaultClassifier
+	}
+	expected := policy.ExpectedAttemptTime
+	if expected == 0 {
+		expected = policy.AttemptTimeout
+	}
+
+	counter, ok := ctx.Value(attemptKey{}).(*attemptCounter)
+	if !ok {
+		counter = &attemptCounter{}
+		context.WithLocalValue(ctx, attemptKey{}, counter)
+	}
+
+	// Restore the attempt of an enclosing Do, or 0.
+	previousAttempt := counter.attempt
+	defer func() {
+		counter.attempt = previousAttempt
+	}()
+
+	var (
+		lastErr error
+		delay   time.Duration
+	)
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err // nolint:wrapcheck // reason: context errors returned as is
+		}
+		if !hasBudget(ctx, expected) {
+			return budgetExhausted(lastErr)
+		}
+
+		counter.attempt = attempt
+
+		lastErr = runAttempt(ctx, fn, policy.AttemptTimeout)
+		if lastErr == nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr // nolint:wrapcheck // reason: context errors returned as is
+		}
+		if !classifier(lastErr) || attempt == policy.MaxAttempts {
+			return lastErr
+		}
+
+		delay = backoff.Delay(attempt, delay)
+		if !hasBudget(ctx, delay+expected) {
+			return budgetExhausted(lastErr)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+
+			return ctx.Err() // nolint:wrapcheck // reason: context errors returned as is
+		}
+	}
+}
+
+func runAttempt(ctx context.Context, fn func(ctx context.Context) error, timeout time.Duration) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(attemptCtx)
+	if err != nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return &attemptTimeoutError{err: err}
+	}
+
+	return err
+}
+
+// hasBudget reports whether ctx has at least needed time left before its deadline.
+func hasBudget(ctx context.Context, needed time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+
+	return !ok || time.Until(deadline) >= needed
+}
+
+func budgetExhausted(lastErr error) error {
+	if lastErr == nil {
+		return ErrBudgetExhausted
+	}
+
+	return fmt.Errorf("%w: %w", ErrBudgetExhausted, lastErr)
+}
@@ -0,0 +1,218 @@
+package retry_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/retry"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+func Test_Do(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	var attempts []int
+	err := retry.Do(ctx, func(ctx context.Context) error {
+		attempts = append(attempts, retry.Attempt(ctx))
+		if len(attempts) < 3 {
+			return errUnavailable
+		}
+
+		return nil
+	}, retry.Policy{
+		Backoff: retry.Constant{Interval: time.Millisecond},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+	assert.Equal(t, 0, retry.Attempt(ctx))
+}
+
+func Test_Do_max_attempts(t *testing.T) {
+	t.Parallel()
+
+	var attempts int
+	err := retry.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+
+		return errUnavailable
+	}, retry.Policy{
+		MaxAttempts: 2,
+		Backoff:     retry.Constant{Interval: time.Millisecond},
+	})
+
+	assert.ErrorIs(t, err, errUnavailable)
+	assert.Equal(t, 2, attempts)
+}
+
+func Test_Do_permanent(t *testing.T) {
+	t.Parallel()
+
+	var attempts int
+	err := retry.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+
+		return retry.Permanent(errUnavailable)
+	}, retry.Policy{})
+
+	assert.ErrorIs(t, err, errUnavailable)
+	assert.Equal(t, 1, attempts)
+}
+
+func Test_Do_nested(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	var inner []int
+	err := retry.Do(ctx, func(ctx context.Context) error {
+		outer := retry.Attempt(ctx)
+
+		err := retry.Do(ctx, func(ctx context.Context) error {
+			inner = append(inner, retry.Attempt(ctx))
+
+			return nil
+		}, retry.Policy{})
+
+		assert.Equal(t, outer, retry.Attempt(ctx))
+
+		return err
+	}, retry.Policy{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, inner)
+	assert.Equal(t, 0, retry.Attempt(ctx))
+}
+
+func Test_Do_attempt_timeout(t *testing.T) {
+	t.Parallel()
+
+	var (
+		classified []error
+		attempts   int
+	)
+	err := retry.Do(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			<-ctx.Done()
+
+			return ctx.Err()
+		}
+
+		return nil
+	}, retry.Policy{
+		AttemptTimeout: 5 * time.Millisecond,
+		Backoff:        retry.Constant{Interval: time.Millisecond},
+		Classifier: func(err error) bool {
+			classified = append(classified, err)
+
+			return errors.Is(err, retry.ErrAttemptTimeout)
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Len(t, classified, 1)
+	assert.ErrorIs(t, classified[0], retry.ErrAttemptTimeout)
+	assert.ErrorIs(t, classified[0], context.DeadlineExceeded)
+}
+
+func Test_Do_parent_deadline(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := retry.Do(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}, retry.Policy{
+		AttemptTimeout:      time.Second,
+		ExpectedAttemptTime: time.Millisecond,
+		Classifier: func(err error) bool {
+			assert.Fail(t, "the deadline of the parent must not be classified")
+
+			return true
+		},
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, retry.ErrAttemptTimeout)
+}
+
+func Test_Do_budget(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Not even the first attempt fits.
+	err := retry.Do(ctx, func(ctx context.Context) error {
+		assert.Fail(t, "attempt started without budget")
+
+		return nil
+	}, retry.Policy{
+		ExpectedAttemptTime: time.Second,
+	})
+	assert.ErrorIs(t, err, retry.ErrBudgetExhausted)
+
+	// The backoff would leave too little time for another attempt.
+	var attempts int
+	err = retry.Do(ctx, func(ctx context.Context) error {
+		attempts++
+
+		return errUnavailable
+	}, retry.Policy{
+		Backoff:             retry.Constant{Interval: 40 * time.Millisecond},
+		ExpectedAttemptTime: 20 * time.Millisecond,
+	})
+	assert.ErrorIs(t, err, retry.ErrBudgetExhausted)
+	assert.ErrorIs(t, err, errUnavailable)
+	assert.Equal(t, "deadline budget exhausted: unavailable", err.Error())
+	assert.Equal(t, 1, attempts)
+	assert.NoError(t, ctx.Err())
+}
+
+func Test_Backoff(t *testing.T) {
+	t.Parallel()
+
+	exponential := retry.Exponential{Initial: 10 * time.Millisecond, Max: 50 * time.Millisecond}
+	assert.Equal(t, 10*time.Millisecond, exponential.Delay(1, 0))
+	assert.Equal(t, 20*time.Millisecond, exponential.Delay(2, 10*time.Millisecond))
+	assert.Equal(t, 40*time.Millisecond, exponential.Delay(3, 20*time.Millisecond))
+	assert.Equal(t, 50*time.Millisecond, exponential.Delay(4, 40*time.Millisecond))
+
+	jitter := retry.DecorrelatedJitter{Base: 10 * time.Millisecond, Max: 100 * time.Millisecond}
+	previous := time.Duration(0)
+	for attempt := 1; attempt < 20; attempt++ {
+		delay := jitter.Delay(attempt, previous)
+		assert.GreaterOrEqual(t, delay, 10*time.Millisecond)
+		assert.LessOrEqual(t, delay, 100*time.Millisecond)
+		if previous != 0 {
+			assert.Less(t, delay, 3*previous+1)
+		}
+		previous = delay
+	}
+
+	assert.Equal(t, time.Second, retry.Constant{Interval: time.Second}.Delay(5, time.Second))
+
+	// Without Max, delays are capped instead of overflowing.
+	unbounded := retry.Exponential{Initial: time.Second}
+	assert.Equal(t, time.Duration(math.MaxInt64), unbounded.Delay(100, 0))
+	assert.Equal(t, time.Duration(math.MaxInt64), unbounded.Delay(5000, 0))
+
+	unboundedJitter := retry.DecorrelatedJitter{Base: time.Second}
+	for attempt := 1; attempt < 20; attempt++ {
+		delay := unboundedJitter.Delay(attempt, math.MaxInt64/2)
+		assert.GreaterOrEqual(t, delay, time.Second)
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+// Status of a component.
+type Status int
+
+const (
+	// Stopped components are not running, either because they were never
+	// started, were stopped, or returned without error and are not restarted.
+	Stopped Status = iota
+	// Running components are in Run.
+	Running
+	// Restarting components are waiting for their backoff before running again.
+	Restarting
+	// Failed components returned an error and are not restarted.
+	Failed
+)
+
+func (self Status) String() string {
+	switch self {
+	case Stopped:
+		return "stopped"
+	case Running:
+		return "running"
+	case Restarting:
+		return "restarting"
+	case Failed:
+		return "failed"
+	}
+
+	return fmt.Sprintf("Status(%d)", int(self))
+}
+
+// Health of a component.
+type Health struct {
+	Name   string
+	Status Status
+	// Since is the time the component entered Status.
+	Since    time.Time
+	Restarts int
+	// LastError returned by Run, including recovered panics, see gofunc.PanicError.
+	LastError error
+}
+
+func (self *component) currentHealth() Health {
+	self.healthMutex.Lock()
+	defer self.healthMutex.Unlock()
+
+	return self.health
+}
+
+func (self *component) setStatus(status Status, err error) {
+	self.healthMutex.Lock()
+	defer self.healthMutex.Unlock()
+
+	self.health.Status = status
+	self.health.Since = time.Now()
+	if err != nil {
+		self.health.LastError = err
+	}
+	if status == Restarting {
+		self.health.Restarts++
+	}
+}
+
+// start initializes the component and starts its run loop.
+func (self *component) start(parent context.Context) error {
+	ctx, cancel := context.WithCancel(context.WithName(parent, self.name))
+
+	if initializer, ok := self.runnable.(Initializer); ok {
+		if err := <-gofunc.Run(ctx, initializer.Init); err != nil {
+			cancel()
+			self.setStatus(Failed, err)
+
+			return fmt.Errorf("%w: %s: %w", ErrInit, self.name, err)
+		}
+	}
+
+	self.cancel = cancel
+	self.setStatus(Running, nil)
+	self.done = gofunc.Run(ctx, self.loop)
+
+	return nil
+}
+
+// loop runs the component until it is not restarted or ctx is done.
+func (self *component) loop(ctx context.Context) error {
+	backoff := self.initialBackoff
+
+	for {
+		started := time.Now()
+		err := <-gofunc.Exec(ctx, self.runnable)
+
+		if ctx.Err() != nil {
+			self.setStatus(Stopped, err)
+
+			return nil
+		}
+
+		if self.policy == Never || (self.policy == OnFailure && err == nil) {
+			if err != nil {
+				self.setStatus(Failed, err)
+			} else {
+				self.setStatus(Stopped, nil)
+			}
+
+			return nil
+		}
+
+		// A component that ran longer than the max backoff is considered recovered.
+		if time.Since(started) > self.maxBackoff {
+			backoff = self.initialBackoff
+		}
+
+		self.setStatus(Restarting, err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			self.setStatus(Stopped, nil)
+
+			return nil
+		}
+
+		backoff *= 2
+		if backoff > self.maxBackoff {
+			backoff = self.maxBackoff
+		}
+
+		self.setStatus(Running, nil)
+	}
+}
+
+// stop shuts the component down, cancels it, and waits for its run loop.
+func (self *component) stop(ctx context.Context) error {
+	if self.cancel == nil {
+		return nil
+	}
+
+	var shutdownErr error
+	if shutdowner, ok := self.runnable.(Shutdowner); ok {
+		select {
+		case err := <-gofunc.Run(context.WithName(ctx, self.name), shutdowner.Shutdown):
+			if err != nil {
+				shutdownErr = fmt.Errorf("%w: %s: %w", ErrShutdown, self.name, err)
+			}
+		case <-ctx.Done():
+			// Abandon a Shutdown ignoring ctx, and cancel the component anyway.
+			self.cancel()
+			self.cancel = nil
+
+			return fmt.Errorf("%w: %s: %w", ErrStopTimeout, self.name, ctx.Err())
+		}
+	}
+
+	self.cancel()
+	self.cancel = nil
+
+	select {
+	case <-self.done:
+	case <-ctx.Done():
+		stopErr := fmt.Errorf("%w: %s: %w", ErrStopTimeout, self.name, ctx.Err())
+		if shutdownErr != nil {
+			return errors.Wrap(shutdownErr, stopErr)
+		}
+
+		return stopErr
+	}
+
+	return shutdownErr
+}
@@ -0,0 +1,272 @@
+// Package service manages the lifecycle of the long-lived components of a
+// program.
+//
+// Components are gofunc.Runnable values, optionally implementing Initializer
+// and Shutdowner. The Manager starts them in dependency order, each on its own
+// localized Context, restarts them according to their RestartPolicy, and stops
+// them in reverse order.
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+var (
+	// ErrDuplicate is returned when adding a component name twice.
+	ErrDuplicate = errors.New("component already added")
+	// ErrUnknownDependency is returned when a component depends on a component that was not added.
+	ErrUnknownDependency = errors.New("unknown dependency")
+	// ErrDependencyCycle is returned when components depend on each other.
+	ErrDependencyCycle = errors.New("dependency cycle")
+	// ErrStarted is returned when the Manager was already started.
+	ErrStarted = errors.New("manager already started")
+	// ErrInit is returned when a component fails to initialize.
+	ErrInit = errors.New("component failed to initialize")
+	// ErrShutdown is returned when a component fails to shut down.
+	ErrShutdown = errors.New("component failed to shut down")
+	// ErrStopTimeout is returned when Run of a component does not return before the stop Context is done.
+	ErrStopTimeout = errors.New("component did not stop in time")
+)
+
+// Initializer is implemented by components that must be initialized before
+// they run. Init is called once, before the first Run.
+type Initializer interface {
+	Init(ctx context.Context) error
+}
+
+// Shutdowner is implemented by components that shut down gracefully.
+// Shutdown is called before the Context of Run is canceled.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// RestartPolicy decides whether a component is run again once Run returns.
+type RestartPolicy int
+
+const (
+	// Never restart the component.
+	Never RestartPolicy = iota
+	// OnFailure restarts the component when Run returns an error or panics.
+	OnFailure
+	// Always restarts the component.
+	Always
+)
+
+// Option configures a component.
+type Option func(*component)
+
+// DependsOn starts the component after the named components and stops it before them.
+func DependsOn(names ...string) Option {
+	return func(component *component) {
+		component.dependencies = append(component.dependencies, names...)
+	}
+}
+
+// WithRestart sets the RestartPolicy of the component. Defaults to Never.
+func WithRestart(policy RestartPolicy) Option {
+	return func(component *component) {
+		component.policy = policy
+	}
+}
+
+// WithBackoff sets the delay before restarting the component, doubling from
+// initial up to max on consecutive restarts. Defaults to 100ms up to 30s.
+func WithBackoff(initial time.Duration, max time.Duration) Option {
+	return func(component *component) {
+		component.initialBackoff = initial
+		component.maxBackoff = max
+	}
+}
+
+type component struct {
+	name           string
+	runnable       gofunc.Runnable
+	dependencies   []string
+	policy         RestartPolicy
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	cancel context.CancelFunc
+	done   <-chan error
+
+	healthMutex sync.Mutex
+	health      Health
+}
+
+// Manager starts, restarts, and stops components.
+type Manager struct {
+	mutex      sync.Mutex
+	components map[string]*component
+	names      []string
+	order      []*component
+	started    bool
+}
+
+// New returns an empty Manager.
+func New() *Manager {
+	return &Manager{
+		components: map[string]*component{},
+	}
+}
+
+// Add the component runnable under name.
+func (self *Manager) Add(name string, runnable gofunc.Runnable, options ...Option) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.started {
+		return ErrStarted
+	}
+	if _, exists := self.components[name]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicate, name)
+	}
+
+	component := &component{
+		name:           name,
+		runnable:       runnable,
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     30 * time.Second,
+		health: Health{
+			Name:   name,
+			Status: Stopped,
+		},
+	}
+	for _, option := range options {
+		option(component)
+	}
+
+	self.components[name] = component
+	self.names = append(self.names, name)
+
+	return nil
+}
+
+// Start initializes and runs every component in dependency order on a
+// localized Context derived from ctx and named after the component.
+//
+// Init is called before the component runs and before its dependents are
+// started. If a component fails to initialize, the components already started
+// are stopped and the error is returned.
+func (self *Manager) Start(ctx context.Context) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.started {
+		return ErrStarted
+	}
+
+	order, err := self.sort()
+	if err != nil {
+		return err
+	}
+	self.started = true
+
+	for index, component := range order {
+		if err := component.start(ctx); err != nil {
+			stop(ctx, order[:index])
+
+			return err
+		}
+	}
+	self.order = order
+
+	return nil
+}
+
+// Stop every component in reverse dependency order. Each component is shut
+// down, then its Context is canceled and Stop waits for Run to return or for
+// ctx to be done. Returns the first error, either returned by Shutdown or
+// ErrStopTimeout naming the component that was still running when ctx was done.
+func (self *Manager) Stop(ctx context.Context) error {
+	self.mutex.Lock()
+	order := self.order
+	self.order = nil
+	self.mutex.Unlock()
+
+	return stop(ctx, order)
+}
+
+func stop(ctx context.Context, order []*component) error {
+	var firstErr error
+	for index := len(order) - 1; index >= 0; index-- {
+		if err := order[index].stop(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// Health returns the health of every component in the order they were started,
+// or in the order they were added if the Manager is not running.
+func (self *Manager) Health() []Health {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	components := self.order
+	if components == nil {
+		for _, name := range self.names {
+			components = append(components, self.components[name])
+		}
+	}
+
+	health := make([]Health, 0, len(components))
+	for _, component := range components {
+		health = append(health, component.currentHealth())
+	}
+
+	return health
+}
+
+// sort returns the components in dependency order, preserving the order they
+// were added in for independent components.
+func (self *Manager) sort() ([]*component, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	order := make([]*component, 0, len(self.names))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %v", ErrDependencyCycle, append(path, name))
+		}
+		state[name] = visiting
+
+		component := self.components[name]
+		for _, dependency := range component.dependencies {
+			if _, exists := self.components[dependency]; !exists {
+				return fmt.Errorf("%w: %s depends on %s", ErrUnknownDependency, name, dependency)
+			}
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		state[name] = visited
+		order = append(order, component)
+
+		return nil
+	}
+
+	for _, name := range self.names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
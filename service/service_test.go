@@ -0,0 +1,292 @@
+package service_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/service"
+)
+
+type events struct {
+	mutex  sync.Mutex
+	events []string
+}
+
+func (self *events) add(event string) {
+	self.mutex.Lock()
+	self.events = append(self.events, event)
+	self.mutex.Unlock()
+}
+
+func (self *events) list() []string {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return append([]string(nil), self.events...)
+}
+
+type component struct {
+	name    string
+	events  *events
+	initErr error
+	running chan struct{}
+}
+
+func newComponent(name string, events *events) *component {
+	return &component{
+		name:    name,
+		events:  events,
+		running: make(chan struct{}),
+	}
+}
+
+func (self *component) Init(ctx context.Context) error {
+	self.events.add("init " + self.name + " " + context.Name(ctx))
+
+	return self.initErr
+}
+
+func (self *component) Run(ctx context.Context) error {
+	close(self.running)
+	<-ctx.Done()
+	self.events.add("canceled " + self.name)
+
+	return nil
+}
+
+func (self *component) Shutdown(ctx context.Context) error {
+	self.events.add("shutdown " + self.name)
+
+	return nil
+}
+
+func Test_Manager(t *testing.T) {
+	t.Parallel()
+
+	events := &events{}
+	api := newComponent("api", events)
+	database := newComponent("database", events)
+	cache := newComponent("cache", events)
+
+	manager := service.New()
+	assert.NoError(t, manager.Add("api", api, service.DependsOn("database", "cache")))
+	assert.NoError(t, manager.Add("database", database))
+	assert.NoError(t, manager.Add("cache", cache, service.DependsOn("database")))
+	assert.ErrorIs(t, manager.Add("cache", cache), service.ErrDuplicate)
+
+	assert.NoError(t, manager.Start(context.Background()))
+	assert.ErrorIs(t, manager.Start(context.Background()), service.ErrStarted)
+
+	<-api.running
+	<-database.running
+	<-cache.running
+
+	health := manager.Health()
+	assert.Len(t, health, 3)
+	for index, name := range []string{"database", "cache", "api"} {
+		assert.Equal(t, name, health[index].Name)
+		assert.Equal(t, service.Running, health[index].Status)
+	}
+
+	assert.NoError(t, manager.Stop(context.Background()))
+
+	assert.Equal(t, []string{
+		"init database database",
+		"init cache cache",
+		"init api api",
+		"shutdown api",
+		"canceled api",
+		"shutdown cache",
+		"canceled cache",
+		"shutdown database",
+		"canceled database",
+	}, events.list())
+
+	for _, health := range manager.Health() {
+		assert.Equal(t, service.Stopped, health.Status)
+	}
+}
+
+func Test_Manager_dependency_errors(t *testing.T) {
+	t.Parallel()
+
+	events := &events{}
+
+	manager := service.New()
+	assert.NoError(t, manager.Add("api", newComponent("api", events), service.DependsOn("database")))
+	assert.ErrorIs(t, manager.Start(context.Background()), service.ErrUnknownDependency)
+
+	manager = service.New()
+	assert.NoError(t, manager.Add("a", newComponent("a", events), service.DependsOn("b")))
+	assert.NoError(t, manager.Add("b", newComponent("b", events), service.DependsOn("a")))
+	err := manager.Start(context.Background())
+	assert.ErrorIs(t, err, service.ErrDependencyCycle)
+	assert.Equal(t, "dependency cycle: [a b a]", err.Error())
+}
+
+func Test_Manager_init_error(t *testing.T) {
+	t.Parallel()
+
+	events := &events{}
+	database := newComponent("database", events)
+	api := newComponent("api", events)
+	api.initErr = errors.New("bad config")
+
+	manager := service.New()
+	assert.NoError(t, manager.Add("database", database))
+	assert.NoError(t, manager.Add("api", api, service.DependsOn("database")))
+
+	err := manager.Start(context.Background())
+	assert.ErrorIs(t, err, service.ErrInit)
+	assert.ErrorIs(t, err, api.initErr)
+	assert.Equal(t, "component failed to initialize: api: bad config", err.Error())
+
+	assert.Equal(t, []string{
+		"init database database",
+		"init api api",
+		"shutdown database",
+		"canceled database",
+	}, events.list())
+}
+
+type flaky struct {
+	mutex sync.Mutex
+	runs  int
+	done  chan struct{}
+}
+
+func (self *flaky) Run(ctx context.Context) error {
+	self.mutex.Lock()
+	self.runs++
+	runs := self.runs
+	self.mutex.Unlock()
+
+	switch runs {
+	case 1:
+		panic("boom")
+	case 2:
+		return errors.New("failed")
+	default:
+		close(self.done)
+		<-ctx.Done()
+
+		return nil
+	}
+}
+
+func Test_Manager_restart(t *testing.T) {
+	t.Parallel()
+
+	flaky := &flaky{done: make(chan struct{})}
+
+	manager := service.New()
+	assert.NoError(t, manager.Add("flaky", flaky, service.WithRestart(service.OnFailure), service.WithBackoff(time.Millisecond, 10*time.Millisecond)))
+	assert.NoError(t, manager.Start(context.Background()))
+
+	<-flaky.done
+
+	health := manager.Health()[0]
+	assert.Equal(t, service.Running, health.Status)
+	assert.Equal(t, 2, health.Restarts)
+	assert.EqualError(t, health.LastError, "failed")
+
+	assert.NoError(t, manager.Stop(context.Background()))
+}
+
+type once struct {
+	err error
+}
+
+func (self *once) Run(ctx context.Context) error {
+	return self.err
+}
+
+func Test_Manager_no_restart(t *testing.T) {
+	t.Parallel()
+
+	manager := service.New()
+	assert.NoError(t, manager.Add("ok", &once{}, service.WithRestart(service.OnFailure)))
+	assert.NoError(t, manager.Add("failing", &once{err: errors.New("failed")}))
+	assert.NoError(t, manager.Start(context.Background()))
+
+	assert.Eventually(t, func() bool {
+		health := manager.Health()
+
+		return health[0].Status == service.Stopped && health[1].Status == service.Failed
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(t, 0, manager.Health()[1].Restarts)
+	assert.Equal(t, "failed", manager.Health()[1].Status.String())
+
+	assert.NoError(t, manager.Stop(context.Background()))
+}
+
+type stuckComponent struct {
+	release chan struct{}
+}
+
+func (self *stuckComponent) Run(ctx context.Context) error {
+	<-self.release
+
+	return nil
+}
+
+// stuckShutdowner ignores the Context passed to Shutdown.
+type stuckShutdowner struct {
+	stuckComponent
+}
+
+func (self *stuckShutdowner) Shutdown(ctx context.Context) error {
+	<-self.release
+
+	return nil
+}
+
+func Test_Manager_stop_timeout(t *testing.T) {
+	t.Parallel()
+
+	stuck := &stuckComponent{
+		release: make(chan struct{}),
+	}
+	defer close(stuck.release)
+
+	manager := service.New()
+	assert.NoError(t, manager.Add("stuck", stuck))
+	assert.NoError(t, manager.Start(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := manager.Stop(ctx)
+	assert.ErrorIs(t, err, service.ErrStopTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "stuck")
+}
+
+func Test_Manager_shutdown_timeout(t *testing.T) {
+	t.Parallel()
+
+	stuck := &stuckShutdowner{
+		stuckComponent: stuckComponent{
+			release: make(chan struct{}),
+		},
+	}
+	defer close(stuck.release)
+
+	manager := service.New()
+	assert.NoError(t, manager.Add("stuck", stuck))
+	assert.NoError(t, manager.Start(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := manager.Stop(ctx)
+	assert.ErrorIs(t, err, service.ErrStopTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "stuck")
+}
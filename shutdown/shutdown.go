@@ -0,0 +1,212 @@
+// Package shutdown runs the graceful shutdown of a program in phases.
+//
+// When a signal arrives, or Shutdown is called, the Manager:
+//
+// 	1. runs the stop hooks, which stop accepting new work,
+// 	2. drains the gofunc goroutines started on its Context until the drain timeout,
+// 	3. runs the shutdown hooks in the order they were registered,
+// 	4. cancels its Context, hard-canceling the goroutines that are left.
+//
+// The Report lists the goroutines that did not finish in time and the errors
+// returned by hooks.
+package shutdown
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+// ErrHook wraps the errors returned by hooks.
+var ErrHook = errors.New("shutdown hook failed")
+
+// HookFn is run during shutdown. ctx is done once the hook timeout elapses.
+// Hooks still running by then are abandoned and reported with the error of
+// ctx, satisfying errors.Is(err, context.DeadlineExceeded).
+type HookFn func(ctx context.Context) error
+
+type hook struct {
+	name string
+	fn   HookFn
+}
+
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithSignals sets the signals that start the shutdown.
+// Defaults to os.Interrupt and syscall.SIGTERM.
+func WithSignals(signals ...os.Signal) Option {
+	return func(manager *Manager) {
+		manager.signals = signals
+	}
+}
+
+// WithDrainTimeout sets how long running goroutines are waited for.
+// Defaults to 30 seconds.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(manager *Manager) {
+		manager.drainTimeout = timeout
+	}
+}
+
+// WithHookTimeout sets how long each hook may run.
+// Defaults to 10 seconds.
+func WithHookTimeout(timeout time.Duration) Option {
+	return func(manager *Manager) {
+		manager.hookTimeout = timeout
+	}
+}
+
+// Report describes a completed shutdown.
+type Report struct {
+	// Signal that started the shutdown, or nil if Shutdown was called.
+	Signal os.Signal
+	// Stragglers are the goroutines that were still running when the drain timed out.
+	Stragglers []gofunc.Goroutine
+	// Errors returned by hooks, in the order the hooks ran. Each wraps ErrHook.
+	Errors []error
+}
+
+type managerKey struct{}
+
+// Manager coordinates the shutdown of the work started on its Context.
+type Manager struct {
+	signals      []os.Signal
+	drainTimeout time.Duration
+	hookTimeout  time.Duration
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	trigger context.Context
+	stop    context.CancelFunc
+
+	hooksMutex    sync.Mutex
+	stopHooks     []hook
+	shutdownHooks []hook
+
+	once   sync.Once
+	report Report
+}
+
+// New returns a Manager listening for shutdown signals. Work started on the
+// Context of the Manager is drained and finally canceled during shutdown.
+func New(parent context.Context, options ...Option) *Manager {
+	manager := &Manager{
+		signals:      []os.Signal{os.Interrupt, syscall.SIGTERM},
+		drainTimeout: 30 * time.Second,
+		hookTimeout:  10 * time.Second,
+	}
+	for _, option := range options {
+		option(manager)
+	}
+
+	manager.ctx, manager.cancel = context.WithCancel(context.WithValue(parent, managerKey{}, manager))
+	manager.trigger, manager.stop = context.WithSignals(parent, manager.signals...)
+
+	return manager
+}
+
+// Context on which the work of the program is started. It is canceled in the
+// last phase of the shutdown.
+func (self *Manager) Context() context.Context {
+	return self.ctx
+}
+
+// OnStop registers a hook that stops accepting new work, run in the first phase.
+func (self *Manager) OnStop(name string, fn HookFn) {
+	self.hooksMutex.Lock()
+	defer self.hooksMutex.Unlock()
+
+	self.stopHooks = append(self.stopHooks, hook{
+		name: name,
+		fn:   fn,
+	})
+}
+
+// OnShutdown registers a hook run in the third phase, after draining,
+// in registration order.
+func (self *Manager) OnShutdown(name string, fn HookFn) {
+	self.hooksMutex.Lock()
+	defer self.hooksMutex.Unlock()
+
+	self.shutdownHooks = append(self.shutdownHooks, hook{
+		name: name,
+		fn:   fn,
+	})
+}
+
+// Wait blocks until a signal arrives or Shutdown is called, then returns the
+// Report of the shutdown once it completed.
+func (self *Manager) Wait() Report {
+	<-self.trigger.Done()
+
+	return self.run()
+}
+
+// Shutdown starts the shutdown without waiting for a signal and returns its
+// Report once it completed. Subsequent calls return the same Report.
+func (self *Manager) Shutdown() Report {
+	self.stop()
+
+	return self.run()
+}
+
+func (self *Manager) run() Report {
+	self.once.Do(func() {
+		// Restore the default signal behavior so a second signal terminates the program.
+		self.stop()
+
+		var signalErr *context.SignalError
+		if errors.As(self.trigger.Err(), &signalErr) {
+			self.report.Signal = signalErr.Signal()
+		}
+
+		self.hooksMutex.Lock()
+		stopHooks := self.stopHooks
+		shutdownHooks := self.shutdownHooks
+		self.hooksMutex.Unlock()
+
+		self.runHooks(stopHooks)
+		self.drain()
+		self.runHooks(shutdownHooks)
+
+		self.cancel()
+	})
+
+	return self.report
+}
+
+func (self *Manager) runHooks(hooks []hook) {
+	for _, hook := range hooks {
+		ctx, cancel := context.WithTimeout(context.WithName(context.Background(), "shutdown/"+hook.name), self.hookTimeout)
+
+		var err error
+		select {
+		case err = <-gofunc.Run(ctx, gofunc.RunFn(hook.fn)):
+		case <-ctx.Done():
+			// Abandon hooks ignoring ctx so that the shutdown stays bounded.
+			err = ctx.Err()
+		}
+		cancel()
+
+		if err != nil {
+			self.report.Errors = append(self.report.Errors, fmt.Errorf("%w: %s: %w", ErrHook, hook.name, err))
+		}
+	}
+}
+
+func (self *Manager) drain() {
+	ctx, cancel := context.WithTimeout(context.Background(), self.drainTimeout)
+	defer cancel()
+
+	self.report.Stragglers = gofunc.Drain(ctx, func(goroutine gofunc.Goroutine) bool {
+		return context.ImmutableValue(goroutine.Context(), managerKey{}) == self
+	})
+}
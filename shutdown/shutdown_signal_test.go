@@ -0,0 +1,36 @@
+//go:build unix
+// +build unix
+
+package shutdown_test
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/shutdown"
+)
+
+func Test_Manager_Wait_signal(t *testing.T) {
+	manager := shutdown.New(context.Background(), shutdown.WithSignals(syscall.SIGUSR1), shutdown.WithDrainTimeout(time.Second))
+
+	stopped := false
+	manager.OnStop("listener", func(ctx context.Context) error {
+		stopped = true
+
+		return nil
+	})
+
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+
+	report := manager.Wait()
+
+	assert.Equal(t, syscall.SIGUSR1, report.Signal)
+	assert.True(t, stopped)
+	assert.Empty(t, report.Stragglers)
+	assert.Empty(t, report.Errors)
+	assert.ErrorIs(t, manager.Context().Err(), context.Canceled)
+}
@@ -0,0 +1,104 @@
+package shutdown_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+	"github.com/wspowell/context/shutdown"
+)
+
+func Test_Manager_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	manager := shutdown.New(context.Background(), shutdown.WithDrainTimeout(50*time.Millisecond))
+
+	var phases []string
+
+	accepting := make(chan struct{})
+	manager.OnStop("listener", func(ctx context.Context) error {
+		phases = append(phases, "stop")
+		close(accepting)
+
+		return nil
+	})
+	manager.OnShutdown("database", func(ctx context.Context) error {
+		phases = append(phases, "database")
+		assert.NoError(t, manager.Context().Err())
+
+		return nil
+	})
+	manager.OnShutdown("cache", func(ctx context.Context) error {
+		phases = append(phases, "cache")
+
+		return errors.New("flush failed")
+	})
+
+	// Finishes once work is no longer accepted.
+	worker := gofunc.Run(context.WithName(manager.Context(), "worker"), func(ctx context.Context) error {
+		<-accepting
+		phases = append(phases, "worker")
+
+		return nil
+	})
+	// Only finishes once hard-canceled.
+	straggler := gofunc.Run(context.WithName(manager.Context(), "straggler"), func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+	// Not started on the Context of the Manager, so not drained.
+	unrelated, cancelUnrelated := context.WithCancel(context.Background())
+	defer cancelUnrelated()
+	gofunc.Run(unrelated, func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	})
+
+	report := manager.Shutdown()
+
+	assert.Nil(t, report.Signal)
+	assert.Equal(t, []string{"stop", "worker", "database", "cache"}, phases)
+	assert.Len(t, report.Stragglers, 1)
+	assert.Equal(t, "straggler", report.Stragglers[0].Name)
+	assert.Len(t, report.Errors, 1)
+	assert.ErrorIs(t, report.Errors[0], shutdown.ErrHook)
+	assert.Equal(t, "shutdown hook failed: cache: flush failed", report.Errors[0].Error())
+
+	assert.NoError(t, <-worker)
+	assert.ErrorIs(t, <-straggler, context.Canceled)
+	assert.ErrorIs(t, manager.Context().Err(), context.Canceled)
+
+	assert.Equal(t, report, manager.Shutdown())
+}
+
+func Test_Manager_hook_timeout(t *testing.T) {
+	t.Parallel()
+
+	manager := shutdown.New(context.Background(), shutdown.WithHookTimeout(50*time.Millisecond))
+
+	errFlush := errors.New("flush failed")
+	manager.OnShutdown("stuck", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+
+		return nil
+	})
+	manager.OnShutdown("cache", func(ctx context.Context) error {
+		return errFlush
+	})
+
+	start := time.Now()
+	report := manager.Shutdown()
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	assert.Len(t, report.Errors, 2)
+	assert.ErrorIs(t, report.Errors[0], shutdown.ErrHook)
+	assert.ErrorIs(t, report.Errors[0], context.DeadlineExceeded)
+	assert.ErrorIs(t, report.Errors[1], shutdown.ErrHook)
+	assert.ErrorIs(t, report.Errors[1], errFlush)
+}
@@ -0,0 +1,106 @@
+package context
+
+import (
+	// nolint:depguard // reason: errors.Is compatibility with stdlib sentinels
+	gocontext "context"
+	"os"
+	"os/signal"
+)
+
+// SignalError is the error returned by Context.Err when a Context created by
+// WithSignals is canceled because a signal arrived.
+//
+// SignalError satisfies errors.Is(err, Canceled), as well as the stdlib Canceled.
+type SignalError struct {
+	signal os.Signal
+}
+
+// Signal that canceled the Context.
+func (self *SignalError) Signal() os.Signal {
+	return self.signal
+}
+
+func (self *SignalError) Error() string {
+	return "context canceled by signal " + self.signal.String()
+}
+
+func (self *SignalError) InternalCode() string {
+	return CodeCanceled
+}
+
+func (self *SignalError) Is(target error) bool {
+	// nolint:errorlint,goerr113 // reason: sentinel comparison
+	return target == Canceled || target == gocontext.Canceled
+}
+
+// WithSignals returns a copy of parent that is canceled when one of the
+// listed signals arrives, when the returned stop function is called, or when
+// the parent context's Done channel is closed, whichever happens first. If no
+// signals are provided, all incoming signals are relayed, as for signal.Notify.
+//
+// It is equivalent to signal.NotifyContext. Once a signal canceled the Context,
+// Err returns a *SignalError. The stop function unregisters the signal
+// behavior, which, like signal.Reset, may restore the default behavior for a
+// given signal. For example, the default behavior of a Go program receiving
+// os.Interrupt is to exit. Calling stop as soon as possible after the signal
+// arrived restores the default behavior, so a second interrupt terminates the
+// program.
+func WithSignals(parent Context, signals ...os.Signal) (ctx Context, stop CancelFunc) {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+
+	c := &signalCtx{
+		cancelCtx: newCancelCtx(parent),
+		signals:   signals,
+	}
+	propagateCancel(parent, c)
+
+	c.ch = make(chan os.Signal, 1)
+	signal.Notify(c.ch, signals...)
+	if c.Err() == nil {
+		go func() {
+			select {
+			case received := <-c.ch:
+				c.cancel(true, &SignalError{signal: received})
+			case <-c.Done():
+			}
+		}()
+	}
+
+	return c, c.stop
+}
+
+// A signalCtx is canceled when a signal arrives. It embeds a cancelCtx to
+// implement Done and Err.
+type signalCtx struct {
+	cancelCtx
+
+	signals []os.Signal
+	ch      chan os.Signal
+}
+
+func (c *signalCtx) cancel(removeFromParent bool, err error) {
+	c.cancelCtx.cancel(false, err)
+	if removeFromParent {
+		// Remove this signalCtx from its parent cancelCtx's children.
+		removeChild(c.cancelCtx.Context, c)
+	}
+}
+
+func (c *signalCtx) stop() {
+	c.cancel(true, Canceled)
+	signal.Stop(c.ch)
+}
+
+func (c *signalCtx) String() string {
+	name := contextName(c.cancelCtx.Context) + ".WithSignals("
+	for index, sig := range c.signals {
+		if index != 0 {
+			name += ", "
+		}
+		name += sig.String()
+	}
+
+	return name + ")"
+}
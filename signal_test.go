@@ -0,0 +1,63 @@
+//go:build unix
+// +build unix
+
+package context_test
+
+import (
+	gocontext "context" // nolint:depguard // reason: testing stdlib compatibility
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+)
+
+func Test_WithSignals(t *testing.T) {
+	ctx, stop := context.WithSignals(context.WithName(context.Background(), "main"), syscall.SIGUSR1)
+	defer stop()
+
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	assert.Contains(t, context.Tree(ctx).String(), "WithSignals")
+	assert.Contains(t, ctx.(interface{ String() string }).String(), ".WithSignals(user defined signal 1)")
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+
+	select {
+	case <-child.Done():
+	case <-time.After(time.Second):
+		assert.Fail(t, "signal did not cancel the context")
+
+		return
+	}
+
+	var signalErr *context.SignalError
+	assert.True(t, errors.As(child.Err(), &signalErr))
+	assert.Equal(t, syscall.SIGUSR1, signalErr.Signal())
+	assert.Equal(t, "context canceled by signal user defined signal 1", ctx.Err().Error())
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.ErrorIs(t, ctx.Err(), gocontext.Canceled)
+	assert.Equal(t, "CTX0", context.InternalCode(ctx.Err()))
+}
+
+func Test_WithSignals_stop(t *testing.T) {
+	ctx, stop := context.WithSignals(context.Background(), syscall.SIGUSR2)
+	stop()
+	stop()
+
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
+
+func Test_WithSignals_parent_canceled(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	ctx, stop := context.WithSignals(parent, syscall.SIGUSR2)
+	defer stop()
+
+	cancel()
+	<-ctx.Done()
+
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
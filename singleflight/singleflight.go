@@ -0,0 +1,121 @@
+// Package singleflight suppresses duplicate concurrent calls.
+//
+// Concurrent callers of Group.Do with the same key share one execution. The
+// execution runs on a Context detached from the cancelation of every caller,
+// so one caller giving up does not fail the others. Each caller stops waiting
+// when its own Context is done, and the execution is canceled once every
+// waiting caller has gone.
+package singleflight
+
+import (
+	"sync"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+// Func is the call shared by the callers of a key.
+type Func[V any] func(ctx context.Context) (V, error)
+
+type call[V any] struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	shared  bool
+
+	value V
+	err   error
+}
+
+// Group of calls keyed by K with results of type V.
+// The zero value is ready to use. A Group must not be copied after first use.
+type Group[K comparable, V any] struct {
+	mutex sync.Mutex
+	calls map[K]*call[V]
+}
+
+// Do calls fn, unless a call for key is already in flight, in which case the
+// result of that call is awaited instead. shared reports whether the result
+// was given to more than one caller.
+//
+// fn runs using gofunc.Run on a Context that keeps the values of the ctx of
+// the caller that started the call, but not its cancelation or deadline. If
+// ctx is done before the call completes, Do returns ctx.Err() without waiting.
+// The Context of fn is canceled when no caller is waiting anymore, and the
+// next call for key starts a new execution.
+func (self *Group[K, V]) Do(ctx context.Context, key K, fn Func[V]) (value V, shared bool, err error) {
+	self.mutex.Lock()
+	if self.calls == nil {
+		self.calls = map[K]*call[V]{}
+	}
+
+	flight, exists := self.calls[key]
+	if exists {
+		flight.waiters++
+		flight.shared = true
+	} else {
+		flight = &call[V]{
+			done:    make(chan struct{}),
+			waiters: 1,
+		}
+		var callCtx context.Context
+		callCtx, flight.cancel = context.WithCancel(context.WithoutCancel(ctx))
+		self.calls[key] = flight
+
+		go self.run(callCtx, key, flight, fn)
+	}
+	self.mutex.Unlock()
+
+	select {
+	case <-flight.done:
+		return flight.value, flight.shared, flight.err
+	case <-ctx.Done():
+		self.mutex.Lock()
+		flight.waiters--
+		if flight.waiters == 0 {
+			self.forget(key, flight)
+			flight.cancel()
+		}
+		self.mutex.Unlock()
+
+		return value, false, ctx.Err()
+	}
+}
+
+// Forget key, so that the next call of Do for key starts a new execution
+// instead of waiting on the call in flight. Callers already waiting still
+// receive the result of the call in flight.
+func (self *Group[K, V]) Forget(key K) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if flight, exists := self.calls[key]; exists {
+		self.forget(key, flight)
+	}
+}
+
+func (self *Group[K, V]) run(ctx context.Context, key K, flight *call[V], fn Func[V]) {
+	var value V
+	err := <-gofunc.Run(ctx, func(ctx context.Context) error {
+		var err error
+		value, err = fn(ctx)
+
+		return err
+	})
+
+	self.mutex.Lock()
+	self.forget(key, flight)
+	flight.value = value
+	flight.err = err
+	self.mutex.Unlock()
+
+	flight.cancel()
+	close(flight.done)
+}
+
+// forget key if it still refers to flight. Requires the mutex to be held.
+func (self *Group[K, V]) forget(key K, flight *call[V]) {
+	if self.calls[key] == flight {
+		delete(self.calls, key)
+	}
+}
@@ -0,0 +1,213 @@
+package singleflight_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/singleflight"
+)
+
+type valueKey struct{}
+
+func Test_Group_Do(t *testing.T) {
+	t.Parallel()
+
+	var (
+		group   singleflight.Group[string, int]
+		calls   atomic.Int32
+		release = make(chan struct{})
+	)
+
+	fn := func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+
+		return 42, nil
+	}
+
+	const callers = 10
+
+	started := make(chan struct{}, callers)
+	values := make([]int, callers)
+	shared := make([]bool, callers)
+
+	var wg sync.WaitGroup
+	for index := 0; index < callers; index++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+
+			started <- struct{}{}
+			var err error
+			values[index], shared[index], err = group.Do(context.Background(), "key", fn)
+			assert.NoError(t, err)
+		}(index)
+	}
+	for index := 0; index < callers; index++ {
+		<-started
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for index := 0; index < callers; index++ {
+		assert.Equal(t, 42, values[index])
+		assert.True(t, shared[index])
+	}
+
+	value, isShared, err := group.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+		return 7, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 7, value)
+	assert.False(t, isShared)
+}
+
+func Test_Group_Do_values(t *testing.T) {
+	t.Parallel()
+
+	var group singleflight.Group[string, string]
+
+	ctx := context.WithValue(context.Background(), valueKey{}, "value")
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	value, _, err := group.Do(ctx, "key", func(ctx context.Context) (string, error) {
+		if _, hasDeadline := ctx.Deadline(); hasDeadline {
+			return "", errors.New("unexpected deadline")
+		}
+
+		return ctx.Value(valueKey{}).(string), nil // nolint:forcetypeassert // reason: test value
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+}
+
+func Test_Group_Do_caller_gives_up(t *testing.T) {
+	t.Parallel()
+
+	var group singleflight.Group[int, string]
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (string, error) {
+		close(started)
+		select {
+		case <-release:
+			return "done", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	impatient, cancel := context.WithCancel(context.Background())
+	impatientErr := make(chan error, 1)
+	go func() {
+		_, _, err := group.Do(impatient, 1, fn)
+		impatientErr <- err
+	}()
+	<-started
+
+	patient := make(chan string, 1)
+	go func() {
+		value, _, _ := group.Do(context.Background(), 1, fn)
+		patient <- value
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-impatientErr, context.Canceled)
+
+	close(release)
+	assert.Equal(t, "done", <-patient)
+}
+
+func Test_Group_Do_all_callers_gone(t *testing.T) {
+	t.Parallel()
+
+	var group singleflight.Group[int, string]
+
+	started := make(chan struct{})
+	stopped := make(chan error, 1)
+	fn := func(ctx context.Context) (string, error) {
+		close(started)
+		<-ctx.Done()
+		stopped <- ctx.Err()
+
+		return "", ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	callerErr := make(chan error, 1)
+	go func() {
+		_, _, err := group.Do(ctx, 1, fn)
+		callerErr <- err
+	}()
+	<-started
+
+	cancel()
+	assert.ErrorIs(t, <-callerErr, context.Canceled)
+
+	select {
+	case err := <-stopped:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "shared call not canceled")
+	}
+
+	value, _, err := group.Do(context.Background(), 1, func(ctx context.Context) (string, error) {
+		return "fresh", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "fresh", value)
+}
+
+func Test_Group_Do_panic(t *testing.T) {
+	t.Parallel()
+
+	var group singleflight.Group[string, int]
+
+	_, _, err := group.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+		panic("boom")
+	})
+	assert.ErrorIs(t, err, errors.ErrPanic)
+}
+
+func Test_Group_Forget(t *testing.T) {
+	t.Parallel()
+
+	var group singleflight.Group[string, int]
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	first := make(chan int, 1)
+	go func() {
+		value, _, _ := group.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+			close(started)
+			<-release
+
+			return 1, nil
+		})
+		first <- value
+	}()
+	<-started
+
+	group.Forget("key")
+
+	value, shared, err := group.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+		return 2, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, value)
+	assert.False(t, shared)
+
+	close(release)
+	assert.Equal(t, 1, <-first)
+}
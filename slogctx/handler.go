@@ -0,0 +1,71 @@
+package slogctx
+
+import (
+	gocontext "context" // nolint:depguard // reason: slog.Handler is defined on the stdlib context
+	"log/slog"
+
+	"github.com/wspowell/context"
+)
+
+// Handler is a slog.Handler adding the log attributes of the Context passed to
+// the Logger, see WithLogAttrs and AddLocalLogAttrs, to every record before
+// passing it to the wrapped handler.
+type Handler struct {
+	next slog.Handler
+}
+
+// NewHandler wraps next.
+//
+// 	logger := slog.New(slogctx.NewHandler(slog.NewJSONHandler(os.Stdout, nil)))
+// 	logger.InfoContext(ctx, "charged")
+func NewHandler(next slog.Handler) *Handler {
+	return &Handler{
+		next: next,
+	}
+}
+
+// Enabled implements slog.Handler.
+func (self *Handler) Enabled(ctx gocontext.Context, level slog.Level) bool {
+	return self.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+//
+// Logging with a Context localized to another goroutine only adds its
+// immutable log attributes, instead of panicking in debug builds.
+func (self *Handler) Handle(ctx gocontext.Context, record slog.Record) error {
+	if ctx != nil {
+		if attrs := handlerLogAttrs(ctx); len(attrs) != 0 {
+			record = record.Clone()
+			record.AddAttrs(attrs...)
+		}
+	}
+
+	return self.next.Handle(ctx, record) // nolint:wrapcheck // reason: handler errors returned as is
+}
+
+// WithAttrs implements slog.Handler.
+func (self *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewHandler(self.next.WithAttrs(attrs))
+}
+
+// WithGroup implements slog.Handler.
+func (self *Handler) WithGroup(name string) slog.Handler {
+	return NewHandler(self.next.WithGroup(name))
+}
+
+// handlerLogAttrs returns the log attributes of ctx, or only its immutable log
+// attributes if reading its local log attributes is a locality violation.
+func handlerLogAttrs(ctx context.Context) (attrs []slog.Attr) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if err, ok := recovered.(error); !ok || context.InternalCode(err) != context.CodeViolation {
+				panic(recovered)
+			}
+
+			attrs, _ = context.ImmutableValue(ctx, attrsKey{}).([]slog.Attr)
+		}
+	}()
+
+	return LogAttrs(ctx)
+}
@@ -0,0 +1,38 @@
+//go:build !release
+// +build !release
+
+package slogctx_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/slogctx"
+)
+
+func Test_Handler_other_goroutine(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bytes.Buffer{}
+	logger := newLogger(buffer)
+
+	ctx := context.Background()
+	ctx = slogctx.WithLogAttrs(ctx, slog.String("request_id", "r1"))
+	slogctx.AddLocalLogAttrs(ctx, slog.Int("attempt", 1))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		assert.NotPanics(t, func() {
+			logger.InfoContext(ctx, "elsewhere")
+		})
+	}()
+	<-done
+
+	assert.Equal(t, "level=INFO msg=elsewhere request_id=r1\n", buffer.String())
+}
@@ -0,0 +1,73 @@
+// Package slogctx carries log/slog attributes on a Context.
+//
+// Request wide attributes are stored as an immutable value using WithLogAttrs.
+// Attributes gathered while processing are accumulated in a local value using
+// AddLocalLogAttrs. Goroutines that Localize the Context inherit a copy of the
+// local attributes, so they keep the fields of the request without sharing a
+// mutable buffer. Handler adds both to every record logged with the Context.
+package slogctx
+
+import (
+	"log/slog"
+
+	"github.com/wspowell/context"
+)
+
+type (
+	attrsKey      struct{}
+	localAttrsKey struct{}
+)
+
+// WithLogAttrs returns a copy of ctx with attrs added to its immutable log attributes.
+func WithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+
+	combined := make([]slog.Attr, 0, len(existing)+len(attrs))
+	combined = append(combined, existing...)
+	combined = append(combined, attrs...)
+
+	return context.WithValue(ctx, attrsKey{}, combined)
+}
+
+// AddLocalLogAttrs adds attrs to the log attributes local to the goroutine ctx
+// is localized to. Panics if ctx is not localized to the current goroutine.
+func AddLocalLogAttrs(ctx context.Context, attrs ...slog.Attr) {
+	if local, ok := ctx.Value(localAttrsKey{}).(*localAttrs); ok {
+		local.attrs = append(local.attrs, attrs...)
+
+		return
+	}
+
+	context.WithLocalValue(ctx, localAttrsKey{}, &localAttrs{
+		attrs: append([]slog.Attr(nil), attrs...),
+	})
+}
+
+// LogAttrs returns the immutable log attributes of ctx followed by its local
+// log attributes.
+func LogAttrs(ctx context.Context) []slog.Attr {
+	immutable, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	local, _ := ctx.Value(localAttrsKey{}).(*localAttrs)
+
+	if local == nil {
+		return immutable
+	}
+
+	attrs := make([]slog.Attr, 0, len(immutable)+len(local.attrs))
+	attrs = append(attrs, immutable...)
+	attrs = append(attrs, local.attrs...)
+
+	return attrs
+}
+
+// localAttrs is the buffer of local log attributes of a goroutine.
+type localAttrs struct {
+	attrs []slog.Attr
+}
+
+// Localize copies the attributes gathered so far into the new goroutine.
+func (self *localAttrs) Localize() any {
+	return &localAttrs{
+		attrs: append([]slog.Attr(nil), self.attrs...),
+	}
+}
@@ -0,0 +1,78 @@
+package slogctx_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+	"github.com/wspowell/context/slogctx"
+)
+
+func newLogger(buffer *bytes.Buffer) *slog.Logger {
+	return slog.New(slogctx.NewHandler(slog.NewTextHandler(buffer, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+
+			return attr
+		},
+	})))
+}
+
+func Test_Handler(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bytes.Buffer{}
+	logger := newLogger(buffer)
+
+	ctx := context.Background()
+	ctx = slogctx.WithLogAttrs(ctx, slog.String("request_id", "r1"))
+	slogctx.AddLocalLogAttrs(ctx, slog.Int("attempt", 1))
+	slogctx.AddLocalLogAttrs(ctx, slog.String("user", "alice"))
+
+	logger.With(slog.String("component", "checkout")).InfoContext(ctx, "charged", slog.Int("cents", 100))
+	assert.Equal(t, "level=INFO msg=charged component=checkout cents=100 request_id=r1 attempt=1 user=alice\n", buffer.String())
+
+	buffer.Reset()
+	logger.InfoContext(context.TODO(), "plain")
+	assert.Equal(t, "level=INFO msg=plain\n", buffer.String())
+}
+
+func Test_WithLogAttrs_immutable(t *testing.T) {
+	t.Parallel()
+
+	parent := slogctx.WithLogAttrs(context.TODO(), slog.String("a", "1"))
+	first := slogctx.WithLogAttrs(parent, slog.String("b", "2"))
+	second := slogctx.WithLogAttrs(parent, slog.String("c", "3"))
+
+	assert.Equal(t, []slog.Attr{slog.String("a", "1")}, slogctx.LogAttrs(parent))
+	assert.Equal(t, []slog.Attr{slog.String("a", "1"), slog.String("b", "2")}, slogctx.LogAttrs(first))
+	assert.Equal(t, []slog.Attr{slog.String("a", "1"), slog.String("c", "3")}, slogctx.LogAttrs(second))
+}
+
+func Test_AddLocalLogAttrs_Localize(t *testing.T) {
+	t.Parallel()
+
+	ctx := slogctx.WithLogAttrs(context.Background(), slog.String("request_id", "r1"))
+	slogctx.AddLocalLogAttrs(ctx, slog.String("stage", "parent"))
+
+	err := <-gofunc.Run(ctx, func(ctx context.Context) error {
+		// Inherited, but not shared with the parent goroutine.
+		assert.Equal(t, []slog.Attr{slog.String("request_id", "r1"), slog.String("stage", "parent")}, slogctx.LogAttrs(ctx))
+		slogctx.AddLocalLogAttrs(ctx, slog.String("worker", "1"))
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []slog.Attr{slog.String("request_id", "r1"), slog.String("stage", "parent")}, slogctx.LogAttrs(ctx))
+
+	assert.Panics(t, func() {
+		slogctx.AddLocalLogAttrs(context.TODO(), slog.String("stage", "unlocalized"))
+	})
+}
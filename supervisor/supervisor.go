@@ -0,0 +1,374 @@
+// Package supervisor implements Erlang style supervision of gofunc goroutines.
+//
+// A Supervisor runs its children, each on a Context named after the child and
+// localized to the child goroutine, and restarts them according to its
+// Strategy when they fail. If children are restarted more often than the
+// restart intensity allows, the Supervisor stops every child and fails with
+// ErrIntensity. A Supervisor is itself a gofunc.Runnable, so adding it as the
+// child of another Supervisor escalates the failure to its parent.
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+)
+
+var (
+	// ErrIntensity is returned by Run when the restart intensity is exceeded.
+	ErrIntensity = errors.New("restart intensity exceeded")
+	// ErrShutdownTimeout is returned by Run when children did not return
+	// within the shutdown timeout and were abandoned.
+	ErrShutdownTimeout = errors.New("children abandoned after shutdown timeout")
+)
+
+// Strategy decides which children are restarted when a child fails.
+type Strategy int
+
+const (
+	// OneForOne restarts only the failed child.
+	OneForOne Strategy = iota
+	// OneForAll stops every other child and restarts all children.
+	OneForAll
+	// RestForOne stops the children added after the failed child and restarts
+	// the failed child and those children.
+	RestForOne
+)
+
+// Restart decides whether a child is restarted once it returns.
+type Restart int
+
+const (
+	// Permanent children are always restarted.
+	Permanent Restart = iota
+	// Transient children are restarted only if they return an error or panic.
+	Transient
+	// Temporary children are never restarted.
+	Temporary
+)
+
+// Option configures a Supervisor.
+type Option func(*Supervisor)
+
+// WithIntensity allows at most maxRestarts restarts within window.
+// Defaults to 3 restarts within 5 seconds.
+func WithIntensity(maxRestarts int, window time.Duration) Option {
+	return func(supervisor *Supervisor) {
+		supervisor.maxRestarts = maxRestarts
+		supervisor.window = window
+	}
+}
+
+// WithShutdownTimeout waits at most timeout for each stopped child to return.
+// Children that do not return in time are abandoned and reported by Run with
+// ErrShutdownTimeout. Defaults to waiting until every child returns.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(supervisor *Supervisor) {
+		supervisor.shutdownTimeout = timeout
+	}
+}
+
+// ChildOption configures a child.
+type ChildOption func(*child)
+
+// WithRestart sets the Restart of the child. Defaults to Permanent.
+func WithRestart(restart Restart) ChildOption {
+	return func(child *child) {
+		child.restart = restart
+	}
+}
+
+// WithLocal sets a local value on the Context of every instance of the child,
+// built by factory in the child goroutine. Restarted children get new values,
+// never the values of the instance that failed.
+func WithLocal(key any, factory func() any) ChildOption {
+	return func(child *child) {
+		child.locals = append(child.locals, local{
+			key:     key,
+			factory: factory,
+		})
+	}
+}
+
+type local struct {
+	key     any
+	factory func() any
+}
+
+type child struct {
+	name     string
+	runnable gofunc.Runnable
+	restart  Restart
+	locals   []local
+}
+
+// restarts reports whether the child is restarted after returning err.
+func (self *child) restarts(err error) bool {
+	return self.restart == Permanent || (self.restart == Transient && err != nil)
+}
+
+// run the child on ctx after setting its local values.
+func (self *child) run(ctx context.Context) error {
+	for _, local := range self.locals {
+		context.WithLocalValue(ctx, local.key, local.factory())
+	}
+
+	return self.runnable.Run(ctx)
+}
+
+// Supervisor runs and restarts children.
+type Supervisor struct {
+	strategy        Strategy
+	maxRestarts     int
+	window          time.Duration
+	shutdownTimeout time.Duration
+
+	mutex    sync.Mutex
+	children []*child
+}
+
+// New returns a Supervisor without children.
+func New(strategy Strategy, options ...Option) *Supervisor {
+	supervisor := &Supervisor{
+		strategy:    strategy,
+		maxRestarts: 3,
+		window:      5 * time.Second,
+	}
+	for _, option := range options {
+		option(supervisor)
+	}
+
+	return supervisor
+}
+
+// Add a child. Children are started in the order they were added and stopped
+// in reverse order. Children added while the Supervisor is running are started
+// the next time it runs.
+func (self *Supervisor) Add(name string, runnable gofunc.Runnable, options ...ChildOption) {
+	child := &child{
+		name:     name,
+		runnable: runnable,
+	}
+	for _, option := range options {
+		option(child)
+	}
+
+	self.mutex.Lock()
+	self.children = append(self.children, child)
+	self.mutex.Unlock()
+}
+
+// instance is a running child.
+type instance struct {
+	index   int
+	cancel  context.CancelFunc
+	stopped chan struct{}
+	err     error
+}
+
+// Run the children until ctx is done or the restart intensity is exceeded.
+// Returns nil once ctx is done, after every child returned.
+//
+// Children that do not return within the shutdown timeout, see
+// WithShutdownTimeout, are abandoned. Since a child must not run twice, Run
+// then stops every other child and returns ErrShutdownTimeout naming them.
+func (self *Supervisor) Run(ctx context.Context) error {
+	self.mutex.Lock()
+	children := append([]*child(nil), self.children...)
+	self.mutex.Unlock()
+
+	run := &run{
+		ctx:             ctx,
+		children:        children,
+		instances:       make([]*instance, len(children)),
+		exits:           make(chan *instance),
+		done:            make(chan struct{}),
+		shutdownTimeout: self.shutdownTimeout,
+	}
+	defer close(run.done)
+
+	for index := range children {
+		run.start(index)
+	}
+
+	var restarts []time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			_, abandoned := run.stop(0)
+
+			return abandonedError(abandoned)
+		case exited := <-run.exits:
+			if run.instances[exited.index] != exited {
+				// Stopped by the Supervisor.
+				continue
+			}
+			run.instances[exited.index] = nil
+
+			child := children[exited.index]
+			if !child.restarts(exited.err) {
+				continue
+			}
+
+			now := time.Now()
+			restarts = append(restarts, now)
+			for len(restarts) != 0 && now.Sub(restarts[0]) > self.window {
+				restarts = restarts[1:]
+			}
+			if len(restarts) > self.maxRestarts {
+				err := fmt.Errorf("%w: %s: %w", ErrIntensity, child.name, exited.err)
+				if _, abandoned := run.stop(0); len(abandoned) != 0 {
+					return errors.Wrap(abandonedError(abandoned), err)
+				}
+
+				return err
+			}
+
+			var (
+				stopped   []int
+				abandoned []string
+			)
+			switch self.strategy {
+			case OneForOne:
+			case OneForAll:
+				stopped, abandoned = run.stop(0)
+			case RestForOne:
+				stopped, abandoned = run.stop(exited.index)
+			}
+
+			if len(abandoned) != 0 {
+				_, remaining := run.stop(0)
+
+				return abandonedError(append(abandoned, remaining...))
+			}
+
+			run.restart(exited.index, stopped)
+		}
+	}
+}
+
+// abandonedError returns ErrShutdownTimeout naming the abandoned children, or
+// nil if there are none.
+func abandonedError(abandoned []string) error {
+	if len(abandoned) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrShutdownTimeout, strings.Join(abandoned, ", "))
+}
+
+// run is the state of a single Run of a Supervisor.
+type run struct {
+	ctx       context.Context
+	children  []*child
+	instances []*instance
+	exits     chan *instance
+	// done is closed when Run returns.
+	done            chan struct{}
+	shutdownTimeout time.Duration
+}
+
+func (self *run) start(index int) {
+	child := self.children[index]
+	ctx, cancel := context.WithCancel(context.WithName(self.ctx, child.name))
+
+	started := &instance{
+		index:   index,
+		cancel:  cancel,
+		stopped: make(chan struct{}),
+	}
+	self.instances[index] = started
+
+	done := gofunc.Run(ctx, child.run)
+	go func() {
+		started.err = <-done
+		cancel()
+		close(started.stopped)
+
+		select {
+		case self.exits <- started:
+		case <-self.done:
+		}
+	}()
+}
+
+// stop the running children from index on, in reverse order. Returns the
+// indexes of the children that were running and the names of the children
+// that did not return within the shutdown timeout.
+func (self *run) stop(from int) ([]int, []string) {
+	var (
+		stopped   []int
+		abandoned []string
+	)
+	for index := len(self.instances) - 1; index >= from; index-- {
+		running := self.instances[index]
+		if running == nil {
+			continue
+		}
+
+		self.instances[index] = nil
+
+		select {
+		case <-running.stopped:
+			// Returned on its own before its exit was handled.
+			if !self.children[index].restarts(running.err) {
+				continue
+			}
+		default:
+		}
+
+		stopped = append(stopped, index)
+		running.cancel()
+
+		if !self.wait(running) {
+			abandoned = append(abandoned, self.children[index].name)
+		}
+	}
+
+	return stopped, abandoned
+}
+
+// wait for a canceled instance to return, up to the shutdown timeout.
+// Returns false if it did not return in time.
+func (self *run) wait(running *instance) bool {
+	if self.shutdownTimeout <= 0 {
+		<-running.stopped
+
+		return true
+	}
+
+	timer := time.NewTimer(self.shutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-running.stopped:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// restart the failed child and the stopped children, except Temporary ones.
+// Children that were not running, such as Transient children that completed
+// cleanly, are not restarted.
+func (self *run) restart(failed int, stopped []int) {
+	restart := make([]bool, len(self.children))
+	restart[failed] = true
+	for _, index := range stopped {
+		if self.children[index].restart != Temporary {
+			restart[index] = true
+		}
+	}
+
+	for index := range restart {
+		if restart[index] {
+			self.start(index)
+		}
+	}
+}
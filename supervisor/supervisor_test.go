@@ -0,0 +1,322 @@
+package supervisor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+	"github.com/wspowell/context/supervisor"
+)
+
+type worker struct {
+	name     string
+	started  chan string
+	failures chan error
+}
+
+func newWorker(name string, started chan string) *worker {
+	return &worker{
+		name:     name,
+		started:  started,
+		failures: make(chan error),
+	}
+}
+
+func (self *worker) Run(ctx context.Context) error {
+	self.started <- context.NamePath(ctx)
+
+	select {
+	case err := <-self.failures:
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// receive n names from started.
+func receive(t *testing.T, started chan string, n int) []string {
+	t.Helper()
+
+	names := make([]string, 0, n)
+	for len(names) < n {
+		select {
+		case name := <-started:
+			names = append(names, name)
+		case <-time.After(time.Second):
+			assert.Fail(t, "child not started")
+
+			return names
+		}
+	}
+
+	return names
+}
+
+func startSupervisor(t *testing.T, strategy supervisor.Strategy) (chan string, []*worker, func()) {
+	t.Helper()
+
+	started := make(chan string, 16)
+	workers := []*worker{newWorker("a", started), newWorker("b", started), newWorker("c", started)}
+
+	tree := supervisor.New(strategy)
+	for _, worker := range workers {
+		tree.Add(worker.name, worker)
+	}
+
+	ctx, cancel := context.WithCancel(context.WithName(context.Background(), "tree"))
+	done := gofunc.Exec(ctx, tree)
+
+	assert.ElementsMatch(t, []string{"tree/a", "tree/b", "tree/c"}, receive(t, started, 3))
+
+	return started, workers, func() {
+		cancel()
+		assert.NoError(t, <-done)
+	}
+}
+
+func Test_OneForOne(t *testing.T) {
+	t.Parallel()
+
+	started, workers, stop := startSupervisor(t, supervisor.OneForOne)
+	defer stop()
+
+	workers[1].failures <- errors.New("failed")
+	assert.Equal(t, []string{"tree/b"}, receive(t, started, 1))
+
+	// Returning without error restarts permanent children too.
+	workers[2].failures <- nil
+	assert.Equal(t, []string{"tree/c"}, receive(t, started, 1))
+}
+
+func Test_OneForAll(t *testing.T) {
+	t.Parallel()
+
+	started, workers, stop := startSupervisor(t, supervisor.OneForAll)
+	defer stop()
+
+	workers[1].failures <- errors.New("failed")
+	assert.ElementsMatch(t, []string{"tree/a", "tree/b", "tree/c"}, receive(t, started, 3))
+}
+
+func Test_OneForAll_transient_completed(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan string, 16)
+	transient := newWorker("transient", started)
+	permanent := newWorker("permanent", started)
+
+	tree := supervisor.New(supervisor.OneForAll)
+	tree.Add("transient", transient, supervisor.WithRestart(supervisor.Transient))
+	tree.Add("permanent", permanent)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := gofunc.Exec(ctx, tree)
+	assert.ElementsMatch(t, []string{"transient", "permanent"}, receive(t, started, 2))
+
+	transient.failures <- nil
+	// Let the transient child return before the permanent child fails.
+	time.Sleep(10 * time.Millisecond)
+	permanent.failures <- errors.New("failed")
+	assert.Equal(t, []string{"permanent"}, receive(t, started, 1))
+
+	select {
+	case name := <-started:
+		assert.Fail(t, "unexpected restart of "+name)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+type stuck struct {
+	release chan struct{}
+}
+
+func (self *stuck) Run(ctx context.Context) error {
+	<-self.release
+
+	return nil
+}
+
+func Test_WithShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan string, 16)
+	stuck := &stuck{
+		release: make(chan struct{}),
+	}
+	defer close(stuck.release)
+
+	tree := supervisor.New(supervisor.OneForOne, supervisor.WithShutdownTimeout(10*time.Millisecond))
+	tree.Add("stuck", stuck)
+	tree.Add("worker", newWorker("worker", started))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := gofunc.Exec(ctx, tree)
+	receive(t, started, 1)
+
+	cancel()
+	err := <-done
+	assert.ErrorIs(t, err, supervisor.ErrShutdownTimeout)
+	assert.Equal(t, "children abandoned after shutdown timeout: stuck", err.Error())
+}
+
+func Test_RestForOne(t *testing.T) {
+	t.Parallel()
+
+	started, workers, stop := startSupervisor(t, supervisor.RestForOne)
+	defer stop()
+
+	workers[1].failures <- errors.New("failed")
+	assert.ElementsMatch(t, []string{"tree/b", "tree/c"}, receive(t, started, 2))
+
+	select {
+	case name := <-started:
+		assert.Fail(t, "unexpected restart of "+name)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func Test_Restart_policies(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan string, 16)
+	transient := newWorker("transient", started)
+	temporary := newWorker("temporary", started)
+
+	tree := supervisor.New(supervisor.OneForOne)
+	tree.Add("transient", transient, supervisor.WithRestart(supervisor.Transient))
+	tree.Add("temporary", temporary, supervisor.WithRestart(supervisor.Temporary))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := gofunc.Exec(ctx, tree)
+	assert.ElementsMatch(t, []string{"transient", "temporary"}, receive(t, started, 2))
+
+	transient.failures <- errors.New("failed")
+	assert.Equal(t, []string{"transient"}, receive(t, started, 1))
+
+	transient.failures <- nil
+	temporary.failures <- errors.New("failed")
+
+	select {
+	case name := <-started:
+		assert.Fail(t, "unexpected restart of "+name)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func Test_Intensity(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan string, 16)
+	failing := newWorker("failing", started)
+	other := newWorker("other", started)
+
+	tree := supervisor.New(supervisor.OneForOne, supervisor.WithIntensity(1, time.Minute))
+	tree.Add("failing", failing)
+	tree.Add("other", other)
+
+	done := gofunc.Exec(context.Background(), tree)
+	receive(t, started, 2)
+
+	failing.failures <- errors.New("first")
+	receive(t, started, 1)
+
+	errSecond := errors.New("second")
+	failing.failures <- errSecond
+
+	err := <-done
+	assert.ErrorIs(t, err, supervisor.ErrIntensity)
+	assert.ErrorIs(t, err, errSecond)
+	assert.Equal(t, "restart intensity exceeded: failing: second", err.Error())
+}
+
+func Test_escalation(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan string, 16)
+	failing := newWorker("failing", started)
+
+	child := supervisor.New(supervisor.OneForOne, supervisor.WithIntensity(0, time.Minute))
+	child.Add("failing", failing)
+
+	parent := supervisor.New(supervisor.OneForOne)
+	parent.Add("child", child)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := gofunc.Exec(ctx, parent)
+	assert.Equal(t, []string{"child/failing"}, receive(t, started, 1))
+
+	// The child supervisor gives up and is restarted by its parent.
+	failing.failures <- errors.New("failed")
+	assert.Equal(t, []string{"child/failing"}, receive(t, started, 1))
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+type connectionKey struct{}
+
+type connection struct {
+	id     int
+	closed bool
+}
+
+type connected struct {
+	connections chan *connection
+	failures    chan error
+}
+
+func (self *connected) Run(ctx context.Context) error {
+	conn := ctx.Value(connectionKey{}).(*connection)
+	self.connections <- conn
+
+	select {
+	case err := <-self.failures:
+		conn.closed = true
+
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+func Test_WithLocal(t *testing.T) {
+	t.Parallel()
+
+	var created int
+	child := &connected{
+		connections: make(chan *connection, 1),
+		failures:    make(chan error),
+	}
+
+	tree := supervisor.New(supervisor.OneForOne)
+	tree.Add("connected", child, supervisor.WithLocal(connectionKey{}, func() any {
+		created++
+
+		return &connection{id: created}
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := gofunc.Exec(ctx, tree)
+
+	first := <-child.connections
+	assert.Equal(t, 1, first.id)
+
+	child.failures <- errors.New("failed")
+
+	second := <-child.connections
+	assert.Equal(t, 2, second.id)
+	assert.False(t, second.closed)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
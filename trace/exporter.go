@@ -0,0 +1,82 @@
+package trace
+
+import (
+	"sync"
+
+	"github.com/wspowell/context/tracecontext"
+)
+
+// Exporter receives every sampled Span when it ends.
+// Export is called by the goroutine ending the Span and must be thread safe.
+type Exporter interface {
+	Export(span SpanData)
+}
+
+// nolint:gochecknoglobals // reason: process wide exporter like the default http.ServeMux
+var (
+	exporterMutex sync.RWMutex
+	exporter      Exporter
+)
+
+// SetExporter sets the Exporter of finished Spans. Spans are discarded while no
+// Exporter is set.
+func SetExporter(newExporter Exporter) {
+	exporterMutex.Lock()
+	exporter = newExporter
+	exporterMutex.Unlock()
+}
+
+func export(span SpanData) {
+	exporterMutex.RLock()
+	current := exporter
+	exporterMutex.RUnlock()
+
+	if current != nil {
+		current.Export(span)
+	}
+}
+
+// InMemoryExporter keeps finished Spans in memory, for tests.
+type InMemoryExporter struct {
+	mutex sync.Mutex
+	spans []SpanData
+}
+
+// NewInMemoryExporter returns an empty InMemoryExporter.
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+// Export implements Exporter.
+func (self *InMemoryExporter) Export(span SpanData) {
+	self.mutex.Lock()
+	self.spans = append(self.spans, span)
+	self.mutex.Unlock()
+}
+
+// Spans returns the exported Spans in the order they ended.
+func (self *InMemoryExporter) Spans() []SpanData {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return append([]SpanData(nil), self.spans...)
+}
+
+// Trace returns the exported Spans of the trace with traceID.
+func (self *InMemoryExporter) Trace(traceID tracecontext.TraceID) []SpanData {
+	var spans []SpanData
+	for _, span := range self.Spans() {
+		if span.TraceID == traceID {
+			spans = append(spans, span)
+		}
+	}
+
+	return spans
+}
+
+// Reset discards the exported Spans.
+func (self *InMemoryExporter) Reset() {
+	self.mutex.Lock()
+	self.spans = nil
+	self.mutex.Unlock()
+}
@@ -0,0 +1,244 @@
+package trace
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/wspowell/context/tracecontext"
+)
+
+// Attribute is a key-value pair describing a Span or Event.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Attr returns an Attribute.
+func Attr(key string, value any) Attribute {
+	return Attribute{
+		Key:   key,
+		Value: value,
+	}
+}
+
+// Event is a timestamped annotation of a Span.
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes []Attribute
+}
+
+// SpanData is the snapshot of a finished Span handed to the Exporter.
+type SpanData struct {
+	Name       string
+	TraceID    tracecontext.TraceID
+	SpanID     tracecontext.SpanID
+	ParentID   tracecontext.SpanID
+	Start      time.Time
+	End        time.Time
+	Attributes []Attribute
+	Events     []Event
+	Err        error
+	// Goroutine is true if the Span was created automatically for a Localized goroutine.
+	Goroutine bool
+}
+
+// Span is a timed operation within a trace.
+//
+// The identity of a Span is immutable and safe to share. Its attributes and
+// events are buffered in a local value of the goroutine that started it and are
+// modified through the Context using SetAttributes, AddEvent, and SetError.
+type Span struct {
+	name        string
+	traceParent tracecontext.TraceParent
+	parent      *Span
+	parentID    tracecontext.SpanID
+	start       time.Time
+	buffer      *buffer
+	// stack of the goroutine the Span is active in.
+	stack *stack
+
+	// auto is set on Spans created by Localize. They are only exported if used.
+	auto  bool
+	used  int32
+	ended int32
+}
+
+func newSpan(name string, traceParent tracecontext.TraceParent, parent *Span) *Span {
+	span := &Span{
+		name:        name,
+		traceParent: traceParent.WithParentID(tracecontext.NewSpanID()),
+		parent:      parent,
+		parentID:    traceParent.ParentID,
+		start:       time.Now(),
+	}
+	span.buffer = &buffer{
+		span: span,
+	}
+
+	return span
+}
+
+// Name of the Span.
+func (self *Span) Name() string {
+	return self.name
+}
+
+// TraceID of the trace the Span belongs to.
+func (self *Span) TraceID() tracecontext.TraceID {
+	return self.traceParent.TraceID
+}
+
+// SpanID of the Span.
+func (self *Span) SpanID() tracecontext.SpanID {
+	return self.traceParent.ParentID
+}
+
+// ParentID of the Span, or the zero SpanID for the root of a trace.
+func (self *Span) ParentID() tracecontext.SpanID {
+	return self.parentID
+}
+
+// TraceParent that identifies the Span as the parent of downstream work.
+func (self *Span) TraceParent() tracecontext.TraceParent {
+	return self.traceParent
+}
+
+// End the Span and export it if it is sampled.
+// End must be called by the goroutine that started the Span. Subsequent calls are ignored.
+func (self *Span) End() {
+	if !atomic.CompareAndSwapInt32(&self.ended, 0, 1) {
+		return
+	}
+
+	if self.stack != nil {
+		self.stack.remove(self)
+	}
+
+	if self.auto && atomic.LoadInt32(&self.used) == 0 {
+		return
+	}
+
+	if !self.traceParent.Flags.Sampled() {
+		return
+	}
+
+	export(SpanData{
+		Name:       self.name,
+		TraceID:    self.traceParent.TraceID,
+		SpanID:     self.traceParent.ParentID,
+		ParentID:   self.parentID,
+		Start:      self.start,
+		End:        time.Now(),
+		Attributes: self.buffer.attributes,
+		Events:     self.buffer.events,
+		Err:        self.buffer.err,
+		Goroutine:  self.auto,
+	})
+}
+
+func (self *Span) isEnded() bool {
+	return atomic.LoadInt32(&self.ended) == 1
+}
+
+// markUsed records that an automatic Span, and the automatic Spans it descends
+// from, must be exported.
+func (self *Span) markUsed() {
+	for span := self; span != nil && span.auto; span = span.parent {
+		if atomic.SwapInt32(&span.used, 1) == 1 {
+			return
+		}
+	}
+}
+
+// buffer holds the mutable state of a Span in the stack of the goroutine that
+// owns the Span.
+type buffer struct {
+	span       *Span
+	attributes []Attribute
+	events     []Event
+	err        error
+}
+
+// stack holds the buffers of the Spans active in a goroutine. It is a single
+// local value of the goroutine, so that Spans that ended do not accumulate in
+// its Context. Only the goroutine modifies the stack, copying it on write,
+// since goroutines Localizing its Context read it concurrently.
+type stack struct {
+	entries atomic.Pointer[[]entry]
+}
+
+// entry is the buffer recording the active Span of a Context. In goroutines
+// the Context was Localized into, this is the buffer of the child Span of the
+// goroutine rather than the buffer of the active Span.
+type entry struct {
+	active *Span
+	buffer *buffer
+}
+
+func (self *stack) load() []entry {
+	if entries := self.entries.Load(); entries != nil {
+		return *entries
+	}
+
+	return nil
+}
+
+func (self *stack) push(active *Span, buffer *buffer) {
+	buffer.span.stack = self
+
+	entries := self.load()
+	pushed := append(entries[:len(entries):len(entries)], entry{active: active, buffer: buffer})
+	self.entries.Store(&pushed)
+}
+
+func (self *stack) remove(span *Span) {
+	entries := self.load()
+	for index := len(entries) - 1; index >= 0; index-- {
+		if entries[index].buffer.span == span {
+			removed := append(entries[:index:index], entries[index+1:]...)
+			self.entries.Store(&removed)
+
+			return
+		}
+	}
+}
+
+// find the buffer recording active, or nil if there is none in the goroutine.
+func (self *stack) find(active *Span) *buffer {
+	entries := self.load()
+	for index := len(entries) - 1; index >= 0; index-- {
+		if entries[index].active == active {
+			return entries[index].buffer
+		}
+	}
+
+	return nil
+}
+
+// Localize starts a child Span for the new goroutine of every active Span.
+// Spans that already ended are not continued.
+func (self *stack) Localize() any {
+	localized := &stack{}
+	for _, entry := range self.load() {
+		span := entry.buffer.span
+		if span.isEnded() {
+			continue
+		}
+
+		child := newSpan(span.name, span.traceParent, span)
+		child.auto = true
+		localized.push(entry.active, child.buffer)
+	}
+
+	return localized
+}
+
+// Join ends the child Spans started for the goroutine.
+func (self *stack) Join() {
+	for _, entry := range self.load() {
+		if entry.buffer.span.auto {
+			entry.buffer.span.End()
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Package trace records lightweight spans on top of Context local values,
+// without depending on a tracing SDK.
+//
+// The active Span is an immutable value of the Context, while the attributes
+// and events of a Span are buffered in a local value of the goroutine that
+// started it, so recording requires no locks. When a Context is Localized into
+// a new goroutine, a child Span is created for the goroutine on first use and
+// ended when the goroutine is joined, see context.Join. Goroutines started
+// using gofunc are joined automatically.
+//
+// Span IDs follow W3C Trace Context. Root Spans continue the trace of a
+// tracecontext.TraceParent carried by the Context, and every Span sets its own
+// TraceParent on the Context so that it is propagated downstream.
+package trace
+
+import (
+	"time"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/tracecontext"
+)
+
+type spanKey struct{}
+
+type stackKey struct{}
+
+// Start a Span named name as a child of the active Span of ctx, and return a
+// Context with the new Span active. ctx must be localized to the current
+// goroutine.
+//
+// 	ctx, span := trace.Start(ctx, "charge")
+// 	defer span.End()
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	var span *Span
+	if parent := FromContext(ctx); parent != nil {
+		span = newSpan(name, parent.traceParent, parent)
+	} else if remote, ok := tracecontext.TraceParentFrom(ctx); ok && remote.IsValid() {
+		span = newSpan(name, remote, nil)
+	} else {
+		traceParent := tracecontext.NewTraceParent(true)
+		traceParent.ParentID = tracecontext.SpanID{}
+		span = newSpan(name, traceParent, nil)
+	}
+
+	active, ok := ctx.Value(stackKey{}).(*stack)
+	if !ok {
+		active = &stack{}
+		context.WithLocalValue(ctx, stackKey{}, active)
+	}
+	active.push(span, span.buffer)
+
+	ctx = context.WithValue(ctx, spanKey{}, span)
+	ctx = tracecontext.WithTraceParent(ctx, span.traceParent)
+
+	return ctx, span
+}
+
+// FromContext returns the active Span of ctx, or nil if there is none.
+// In a goroutine the active Span was Localized into, this is the child Span of
+// the goroutine.
+func FromContext(ctx context.Context) *Span {
+	span, ok := ctx.Value(spanKey{}).(*Span)
+	if !ok {
+		return nil
+	}
+
+	if buffer := activeBuffer(ctx, span); buffer != nil {
+		return buffer.span
+	}
+
+	return span
+}
+
+// activeBuffer returns the buffer of span in the current goroutine, or nil if
+// the goroutine cannot record into span.
+func activeBuffer(ctx context.Context, span *Span) *buffer {
+	active, ok := ctx.Value(stackKey{}).(*stack)
+	if !ok {
+		return nil
+	}
+
+	buffer := active.find(span)
+	if buffer == nil || buffer.span.isEnded() {
+		return nil
+	}
+
+	buffer.span.markUsed()
+
+	return buffer
+}
+
+func activeSpanBuffer(ctx context.Context) *buffer {
+	span, ok := ctx.Value(spanKey{}).(*Span)
+	if !ok {
+		return nil
+	}
+
+	return activeBuffer(ctx, span)
+}
+
+// SetAttributes on the active Span of ctx, replacing attributes with the same key.
+func SetAttributes(ctx context.Context, attributes ...Attribute) {
+	buffer := activeSpanBuffer(ctx)
+	if buffer == nil {
+		return
+	}
+
+	for _, attribute := range attributes {
+		replaced := false
+		for index := range buffer.attributes {
+			if buffer.attributes[index].Key == attribute.Key {
+				buffer.attributes[index] = attribute
+				replaced = true
+
+				break
+			}
+		}
+		if !replaced {
+			buffer.attributes = append(buffer.attributes, attribute)
+		}
+	}
+}
+
+// AddEvent to the active Span of ctx.
+func AddEvent(ctx context.Context, name string, attributes ...Attribute) {
+	buffer := activeSpanBuffer(ctx)
+	if buffer == nil {
+		return
+	}
+
+	buffer.events = append(buffer.events, Event{
+		Name:       name,
+		Time:       time.Now(),
+		Attributes: attributes,
+	})
+}
+
+// SetError records err as the outcome of the active Span of ctx.
+func SetError(ctx context.Context, err error) {
+	buffer := activeSpanBuffer(ctx)
+	if buffer == nil {
+		return
+	}
+
+	buffer.err = err
+}
@@ -0,0 +1,149 @@
+package trace_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/gofunc"
+	"github.com/wspowell/context/trace"
+	"github.com/wspowell/context/tracecontext"
+)
+
+// nolint:gochecknoglobals // reason: exporter shared by the tests
+var exporter = trace.NewInMemoryExporter()
+
+// nolint:gochecknoinits // reason: the exporter is process wide
+func init() {
+	trace.SetExporter(exporter)
+}
+
+func Test_Start(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assert.Nil(t, trace.FromContext(ctx))
+
+	ctx, root := trace.Start(ctx, "checkout")
+	assert.Equal(t, root, trace.FromContext(ctx))
+	assert.False(t, root.ParentID().IsValid())
+	trace.SetAttributes(ctx, trace.Attr("user", "alice"), trace.Attr("items", 1))
+	trace.SetAttributes(ctx, trace.Attr("items", 2))
+
+	childCtx, child := trace.Start(ctx, "charge")
+	assert.Equal(t, root.TraceID(), child.TraceID())
+	assert.Equal(t, root.SpanID(), child.ParentID())
+	trace.AddEvent(childCtx, "declined", trace.Attr("retry", true))
+	trace.SetError(childCtx, errors.New("card declined"))
+
+	traceParent, ok := tracecontext.TraceParentFrom(childCtx)
+	assert.True(t, ok)
+	assert.Equal(t, child.TraceParent(), traceParent)
+
+	child.End()
+	root.End()
+	root.End()
+
+	spans := exporter.Trace(root.TraceID())
+	assert.Len(t, spans, 2)
+
+	assert.Equal(t, "charge", spans[0].Name)
+	assert.Equal(t, child.SpanID(), spans[0].SpanID)
+	assert.Len(t, spans[0].Events, 1)
+	assert.Equal(t, "declined", spans[0].Events[0].Name)
+	assert.EqualError(t, spans[0].Err, "card declined")
+
+	assert.Equal(t, "checkout", spans[1].Name)
+	assert.Equal(t, []trace.Attribute{trace.Attr("user", "alice"), trace.Attr("items", 2)}, spans[1].Attributes)
+	assert.False(t, spans[1].End.Before(spans[1].Start))
+
+	// Ended spans are no longer recorded.
+	trace.SetAttributes(ctx, trace.Attr("late", true))
+	assert.Len(t, exporter.Trace(root.TraceID())[1].Attributes, 2)
+}
+
+func Test_Start_locals(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	for i := 0; i < 1000; i++ {
+		_, span := trace.Start(ctx, "query")
+		span.End()
+	}
+
+	// Spans share a single local value of the goroutine.
+	assert.Len(t, context.Tree(ctx).Locals, 1)
+}
+
+func Test_Start_remote_parent(t *testing.T) {
+	t.Parallel()
+
+	remote := tracecontext.NewTraceParent(true)
+
+	ctx := tracecontext.WithTraceParent(context.Background(), remote)
+	_, span := trace.Start(ctx, "handler")
+	span.End()
+
+	assert.Equal(t, remote.TraceID, span.TraceID())
+	assert.Equal(t, remote.ParentID, span.ParentID())
+	assert.Len(t, exporter.Trace(remote.TraceID), 1)
+
+	// Unsampled traces are not exported.
+	remote = tracecontext.NewTraceParent(false)
+
+	ctx = tracecontext.WithTraceParent(context.Background(), remote)
+	_, span = trace.Start(ctx, "handler")
+	span.End()
+
+	assert.Empty(t, exporter.Trace(remote.TraceID))
+}
+
+func Test_Localize_child_span(t *testing.T) {
+	t.Parallel()
+
+	ctx, root := trace.Start(context.Background(), "request")
+
+	err := <-gofunc.Run(ctx, func(ctx context.Context) error {
+		span := trace.FromContext(ctx)
+		assert.NotEqual(t, root, span)
+		assert.Equal(t, root.SpanID(), span.ParentID())
+
+		trace.SetAttributes(ctx, trace.Attr("worker", true))
+
+		_, query := trace.Start(ctx, "query")
+		assert.Equal(t, span.SpanID(), query.ParentID())
+		query.End()
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// Goroutines that do not record anything do not export a Span.
+	err = <-gofunc.Run(ctx, func(ctx context.Context) error {
+		return nil
+	})
+	assert.NoError(t, err)
+
+	root.End()
+
+	// Spans are not continued once ended.
+	err = <-gofunc.Run(ctx, func(ctx context.Context) error {
+		assert.Equal(t, root, trace.FromContext(ctx))
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	spans := exporter.Trace(root.TraceID())
+	assert.Len(t, spans, 3)
+
+	assert.Equal(t, "query", spans[0].Name)
+	assert.Equal(t, "request", spans[1].Name)
+	assert.True(t, spans[1].Goroutine)
+	assert.Equal(t, root.SpanID(), spans[1].ParentID)
+	assert.Equal(t, []trace.Attribute{trace.Attr("worker", true)}, spans[1].Attributes)
+	assert.Equal(t, "request", spans[2].Name)
+	assert.False(t, spans[2].Goroutine)
+}
@@ -0,0 +1,274 @@
+package tracecontext
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	baggageMaxMembers = 64
+	baggageMaxLength  = 8192
+)
+
+// BaggageProperty is metadata attached to a BaggageMember.
+type BaggageProperty struct {
+	Key      string
+	Value    string
+	HasValue bool
+}
+
+func (self BaggageProperty) String() string {
+	if self.HasValue {
+		return self.Key + "=" + encodeBaggageValue(self.Value)
+	}
+
+	return self.Key
+}
+
+// BaggageMember is a single key-value entry of Baggage.
+type BaggageMember struct {
+	Key        string
+	Value      string
+	Properties []BaggageProperty
+}
+
+func (self BaggageMember) String() string {
+	var builder strings.Builder
+	builder.WriteString(self.Key)
+	builder.WriteByte('=')
+	builder.WriteString(encodeBaggageValue(self.Value))
+	for _, property := range self.Properties {
+		builder.WriteByte(';')
+		builder.WriteString(property.String())
+	}
+
+	return builder.String()
+}
+
+// Baggage is the W3C baggage header, carrying user defined properties across
+// services. Baggage is immutable; modifications return a copy.
+//
+// See https://www.w3.org/TR/baggage/
+type Baggage struct {
+	members []BaggageMember
+}
+
+// ParseBaggage parses a baggage header value.
+func ParseBaggage(value string) (Baggage, error) {
+	var baggage Baggage
+
+	if len(value) > baggageMaxLength {
+		return baggage, fmt.Errorf("%w: longer than %d bytes", ErrInvalidBaggage, baggageMaxLength)
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = trimOWS(entry)
+		if entry == "" {
+			continue
+		}
+
+		member, err := parseBaggageMember(entry)
+		if err != nil {
+			return Baggage{}, err
+		}
+
+		baggage.members = append(baggage.members, member)
+		if len(baggage.members) > baggageMaxMembers {
+			return Baggage{}, fmt.Errorf("%w: more than %d members", ErrInvalidBaggage, baggageMaxMembers)
+		}
+	}
+
+	return baggage, nil
+}
+
+func parseBaggageMember(entry string) (BaggageMember, error) {
+	var member BaggageMember
+
+	parts := strings.Split(entry, ";")
+
+	separator := strings.IndexByte(parts[0], '=')
+	if separator < 0 {
+		return member, fmt.Errorf("%w: missing '=' in %q", ErrInvalidBaggage, parts[0])
+	}
+
+	member.Key = trimOWS(parts[0][:separator])
+	if !isToken(member.Key) {
+		return member, fmt.Errorf("%w: invalid key %q", ErrInvalidBaggage, member.Key)
+	}
+
+	value, err := decodeBaggageValue(trimOWS(parts[0][separator+1:]))
+	if err != nil {
+		return member, err
+	}
+	member.Value = value
+
+	for _, part := range parts[1:] {
+		part = trimOWS(part)
+
+		var property BaggageProperty
+		if separator := strings.IndexByte(part, '='); separator >= 0 {
+			property.Key = trimOWS(part[:separator])
+			if property.Value, err = decodeBaggageValue(trimOWS(part[separator+1:])); err != nil {
+				return member, err
+			}
+			property.HasValue = true
+		} else {
+			property.Key = part
+		}
+
+		if !isToken(property.Key) {
+			return member, fmt.Errorf("%w: invalid property key %q", ErrInvalidBaggage, property.Key)
+		}
+
+		member.Properties = append(member.Properties, property)
+	}
+
+	return member, nil
+}
+
+// Len returns the number of members.
+func (self Baggage) Len() int {
+	return len(self.members)
+}
+
+// Members returns a copy of the members.
+func (self Baggage) Members() []BaggageMember {
+	return append([]BaggageMember(nil), self.members...)
+}
+
+// Member returns the member with key.
+func (self Baggage) Member(key string) (BaggageMember, bool) {
+	for _, member := range self.members {
+		if member.Key == key {
+			return member, true
+		}
+	}
+
+	return BaggageMember{}, false
+}
+
+// Get returns the value of the member with key.
+func (self Baggage) Get(key string) (string, bool) {
+	member, ok := self.Member(key)
+
+	return member.Value, ok
+}
+
+// SetMember returns a copy of the Baggage with member added, replacing any
+// existing member with the same key. Fails if the result exceeds the limits of
+// the specification.
+func (self Baggage) SetMember(member BaggageMember) (Baggage, error) {
+	if !isToken(member.Key) {
+		return self, fmt.Errorf("%w: invalid key %q", ErrInvalidBaggage, member.Key)
+	}
+	for _, property := range member.Properties {
+		if !isToken(property.Key) {
+			return self, fmt.Errorf("%w: invalid property key %q", ErrInvalidBaggage, property.Key)
+		}
+	}
+
+	members := make([]BaggageMember, 0, len(self.members)+1)
+	for _, existing := range self.members {
+		if existing.Key != member.Key {
+			members = append(members, existing)
+		}
+	}
+	members = append(members, member)
+
+	baggage := Baggage{members: members}
+	if len(members) > baggageMaxMembers {
+		return self, fmt.Errorf("%w: more than %d members", ErrInvalidBaggage, baggageMaxMembers)
+	}
+	if len(baggage.String()) > baggageMaxLength {
+		return self, fmt.Errorf("%w: longer than %d bytes", ErrInvalidBaggage, baggageMaxLength)
+	}
+
+	return baggage, nil
+}
+
+// Set returns a copy of the Baggage with key set to value.
+func (self Baggage) Set(key string, value string) (Baggage, error) {
+	return self.SetMember(BaggageMember{
+		Key:   key,
+		Value: value,
+	})
+}
+
+// Delete returns a copy of the Baggage without the member with key.
+func (self Baggage) Delete(key string) Baggage {
+	members := make([]BaggageMember, 0, len(self.members))
+	for _, existing := range self.members {
+		if existing.Key != key {
+			members = append(members, existing)
+		}
+	}
+
+	return Baggage{members: members}
+}
+
+// String returns the baggage header value.
+func (self Baggage) String() string {
+	entries := make([]string, len(self.members))
+	for index, member := range self.members {
+		entries[index] = member.String()
+	}
+
+	return strings.Join(entries, ",")
+}
+
+func trimOWS(value string) string {
+	return strings.Trim(value, " \t")
+}
+
+// isToken validates an RFC 7230 token.
+func isToken(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for index := 0; index < len(value); index++ {
+		c := value[index]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isBaggageOctet reports whether c may appear unencoded in a value.
+func isBaggageOctet(c byte) bool {
+	return c == 0x21 || 0x23 <= c && c <= 0x2b || 0x2d <= c && c <= 0x3a || 0x3c <= c && c <= 0x5b || 0x5d <= c && c <= 0x7e
+}
+
+func decodeBaggageValue(value string) (string, error) {
+	for index := 0; index < len(value); index++ {
+		if !isBaggageOctet(value[index]) {
+			return "", fmt.Errorf("%w: invalid value %q", ErrInvalidBaggage, value)
+		}
+	}
+
+	decoded, err := url.PathUnescape(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid percent encoding %q", ErrInvalidBaggage, value)
+	}
+
+	return decoded, nil
+}
+
+func encodeBaggageValue(value string) string {
+	var builder strings.Builder
+	for index := 0; index < len(value); index++ {
+		c := value[index]
+		if isBaggageOctet(c) && c != '%' {
+			builder.WriteByte(c)
+		} else {
+			builder.WriteByte('%')
+			builder.WriteByte("0123456789ABCDEF"[c>>4])
+			builder.WriteByte("0123456789ABCDEF"[c&0x0f])
+		}
+	}
+
+	return builder.String()
+}
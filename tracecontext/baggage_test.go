@@ -0,0 +1,81 @@
+package tracecontext_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context/tracecontext"
+)
+
+func Test_ParseBaggage(t *testing.T) {
+	t.Parallel()
+
+	baggage, err := tracecontext.ParseBaggage("userId=alice, serverNode = DF%2028 ,isProduction=false;ttl=60;secret")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, baggage.Len())
+
+	value, ok := baggage.Get("serverNode")
+	assert.True(t, ok)
+	assert.Equal(t, "DF 28", value)
+
+	member, ok := baggage.Member("isProduction")
+	assert.True(t, ok)
+	assert.Equal(t, []tracecontext.BaggageProperty{
+		{Key: "ttl", Value: "60", HasValue: true},
+		{Key: "secret"},
+	}, member.Properties)
+
+	assert.Equal(t, "userId=alice,serverNode=DF%2028,isProduction=false;ttl=60;secret", baggage.String())
+}
+
+func Test_ParseBaggage_invalid(t *testing.T) {
+	t.Parallel()
+
+	members := make([]string, 65)
+	for index := range members {
+		members[index] = fmt.Sprintf("k%d=v", index)
+	}
+
+	for _, value := range []string{
+		"userId",
+		"user id=alice",
+		"userId=ali\"ce",
+		"userId=%zz",
+		"userId=alice;=1",
+		"key=" + strings.Repeat("x", 8192),
+		strings.Join(members, ","),
+	} {
+		_, err := tracecontext.ParseBaggage(value)
+		assert.True(t, errors.Is(err, tracecontext.ErrInvalidBaggage), value)
+	}
+}
+
+func Test_Baggage_Set(t *testing.T) {
+	t.Parallel()
+
+	var baggage tracecontext.Baggage
+
+	baggage, err := baggage.Set("greeting", "hello, world; 100%")
+	assert.NoError(t, err)
+	assert.Equal(t, "greeting=hello%2C%20world%3B%20100%25", baggage.String())
+
+	parsed, err := tracecontext.ParseBaggage(baggage.String())
+	assert.NoError(t, err)
+	value, _ := parsed.Get("greeting")
+	assert.Equal(t, "hello, world; 100%", value)
+
+	replaced, err := baggage.Set("greeting", "hi")
+	assert.NoError(t, err)
+	assert.Equal(t, "greeting=hi", replaced.String())
+	assert.Equal(t, 0, replaced.Delete("greeting").Len())
+
+	_, err = baggage.Set("bad key", "1")
+	assert.True(t, errors.Is(err, tracecontext.ErrInvalidBaggage))
+
+	_, err = baggage.Set("large", strings.Repeat("x", 8192))
+	assert.True(t, errors.Is(err, tracecontext.ErrInvalidBaggage))
+}
@@ -0,0 +1,111 @@
+// Package tracecontext implements W3C Trace Context and Baggage as typed
+// immutable Context values, without depending on a tracing SDK.
+//
+// Importing the package registers the traceparent, tracestate, and baggage
+// headers with the propagate package, so that they are injected and extracted
+// by any propagate.Carrier, including httpctx.Transport and httpctx.Middleware.
+// A tracestate is only propagated along with a valid traceparent.
+package tracecontext
+
+import (
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/propagate"
+)
+
+// Header names of the W3C specifications.
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+	BaggageHeader     = "baggage"
+)
+
+var (
+	// ErrInvalidTraceParent is returned when a traceparent fails validation.
+	ErrInvalidTraceParent = errors.New("invalid traceparent")
+	// ErrInvalidTraceState is returned when a tracestate fails validation.
+	ErrInvalidTraceState = errors.New("invalid tracestate")
+	// ErrInvalidBaggage is returned when baggage fails validation.
+	ErrInvalidBaggage = errors.New("invalid baggage")
+)
+
+type (
+	traceParentKey struct{}
+	traceStateKey  struct{}
+	baggageKey     struct{}
+)
+
+// nolint:gochecknoinits // reason: registers the W3C headers with propagate on import
+func init() {
+	for _, err := range []error{
+		propagate.Register(TraceParentHeader, traceParentKey{}, propagate.Func(encodeTraceParent, ParseTraceParent)),
+		propagate.Register(TraceStateHeader, traceStateKey{}, propagate.Requires(TraceParentHeader, propagate.Func(encodeTraceState, ParseTraceState))),
+		propagate.Register(BaggageHeader, baggageKey{}, propagate.Func(encodeBaggage, ParseBaggage)),
+	} {
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func encodeTraceParent(traceParent TraceParent) (string, error) {
+	if !traceParent.IsValid() {
+		return "", ErrInvalidTraceParent
+	}
+
+	return traceParent.String(), nil
+}
+
+func encodeTraceState(traceState TraceState) (string, error) {
+	// Vendors must propagate at least 512 characters.
+	return traceState.Truncate(512).String(), nil
+}
+
+func encodeBaggage(baggage Baggage) (string, error) {
+	return baggage.String(), nil
+}
+
+// WithTraceParent returns a copy of ctx carrying traceParent.
+func WithTraceParent(ctx context.Context, traceParent TraceParent) context.Context {
+	return context.WithValue(ctx, traceParentKey{}, traceParent)
+}
+
+// TraceParentFrom returns the TraceParent carried by ctx.
+func TraceParentFrom(ctx context.Context) (TraceParent, bool) {
+	traceParent, ok := ctx.Value(traceParentKey{}).(TraceParent)
+
+	return traceParent, ok
+}
+
+// WithTraceState returns a copy of ctx carrying traceState.
+func WithTraceState(ctx context.Context, traceState TraceState) context.Context {
+	return context.WithValue(ctx, traceStateKey{}, traceState)
+}
+
+// TraceStateFrom returns the TraceState carried by ctx.
+//
+// A TraceState is only meaningful along with its TraceParent, so it is
+// dropped if ctx carries no valid TraceParent, for example when the
+// traceparent header of a request failed to parse.
+func TraceStateFrom(ctx context.Context) (TraceState, bool) {
+	if traceParent, ok := TraceParentFrom(ctx); !ok || !traceParent.IsValid() {
+		return TraceState{}, false
+	}
+
+	traceState, ok := ctx.Value(traceStateKey{}).(TraceState)
+
+	return traceState, ok
+}
+
+// WithBaggage returns a copy of ctx carrying baggage.
+func WithBaggage(ctx context.Context, baggage Baggage) context.Context {
+	return context.WithValue(ctx, baggageKey{}, baggage)
+}
+
+// BaggageFrom returns the Baggage carried by ctx.
+func BaggageFrom(ctx context.Context) (Baggage, bool) {
+	baggage, ok := ctx.Value(baggageKey{}).(Baggage)
+
+	return baggage, ok
+}
@@ -0,0 +1,110 @@
+package tracecontext_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+	"github.com/wspowell/context/httpctx"
+	"github.com/wspowell/context/propagate"
+	"github.com/wspowell/context/tracecontext"
+)
+
+func Test_propagation(t *testing.T) {
+	t.Parallel()
+
+	traceParent := tracecontext.NewTraceParent(true)
+	traceState, err := tracecontext.ParseTraceState("rojo=00f067aa0ba902b7")
+	assert.NoError(t, err)
+	baggage, err := tracecontext.ParseBaggage("userId=alice")
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	ctx = tracecontext.WithTraceParent(ctx, traceParent)
+	ctx = tracecontext.WithTraceState(ctx, traceState)
+	ctx = tracecontext.WithBaggage(ctx, baggage)
+
+	carrier := propagate.MapCarrier{}
+	assert.NoError(t, propagate.Inject(ctx, carrier))
+	assert.Equal(t, traceParent.String(), carrier.Get(tracecontext.TraceParentHeader))
+	assert.Equal(t, "rojo=00f067aa0ba902b7", carrier.Get(tracecontext.TraceStateHeader))
+	assert.Equal(t, "userId=alice", carrier.Get(tracecontext.BaggageHeader))
+
+	extracted, err := propagate.Extract(context.Background(), carrier)
+	assert.NoError(t, err)
+
+	extractedTraceParent, ok := tracecontext.TraceParentFrom(extracted)
+	assert.True(t, ok)
+	assert.Equal(t, traceParent, extractedTraceParent)
+	extractedTraceState, ok := tracecontext.TraceStateFrom(extracted)
+	assert.True(t, ok)
+	assert.Equal(t, traceState, extractedTraceState)
+	extractedBaggage, ok := tracecontext.BaggageFrom(extracted)
+	assert.True(t, ok)
+	assert.Equal(t, baggage, extractedBaggage)
+}
+
+func Test_propagation_http(t *testing.T) {
+	t.Parallel()
+
+	traceParent := tracecontext.NewTraceParent(false)
+
+	var received tracecontext.TraceParent
+	server := httptest.NewServer(httpctx.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = tracecontext.TraceParentFrom(httpctx.From(r))
+	})))
+	defer server.Close()
+
+	ctx := tracecontext.WithTraceParent(context.Background(), traceParent)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+
+	client := &http.Client{Transport: &httpctx.Transport{}}
+	response, err := client.Do(request)
+	assert.NoError(t, err)
+	response.Body.Close()
+
+	assert.Equal(t, traceParent, received)
+}
+
+func Test_propagation_invalid(t *testing.T) {
+	t.Parallel()
+
+	carrier := propagate.MapCarrier{
+		tracecontext.TraceParentHeader: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		tracecontext.TraceStateHeader:  "rojo=00f067aa0ba902b7",
+		tracecontext.BaggageHeader:     "userId=alice",
+	}
+
+	ctx, err := propagate.Extract(context.Background(), carrier)
+	assert.Error(t, err)
+
+	_, ok := tracecontext.TraceParentFrom(ctx)
+	assert.False(t, ok)
+	_, ok = tracecontext.TraceStateFrom(ctx)
+	assert.False(t, ok)
+	_, ok = tracecontext.BaggageFrom(ctx)
+	assert.True(t, ok)
+
+	err = propagate.Inject(tracecontext.WithTraceParent(context.Background(), tracecontext.TraceParent{}), propagate.MapCarrier{})
+	assert.ErrorIs(t, err, propagate.ErrEncode)
+
+	// The tracestate of an invalid traceparent is not forwarded downstream.
+	injected := propagate.MapCarrier{}
+	err = propagate.Inject(ctx, injected)
+	assert.NoError(t, err)
+	assert.Equal(t, propagate.MapCarrier{tracecontext.BaggageHeader: "userId=alice"}, injected)
+
+	// Nor is a tracestate without a traceparent.
+	delete(carrier, tracecontext.TraceParentHeader)
+	ctx, err = propagate.Extract(context.Background(), carrier)
+	assert.NoError(t, err)
+
+	injected = propagate.MapCarrier{}
+	err = propagate.Inject(ctx, injected)
+	assert.NoError(t, err)
+	assert.Equal(t, propagate.MapCarrier{tracecontext.BaggageHeader: "userId=alice"}, injected)
+}
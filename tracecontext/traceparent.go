@@ -0,0 +1,197 @@
+package tracecontext
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	traceParentVersion   = 0x00
+	traceParentLength    = 55
+	traceParentMaxLength = 512
+)
+
+// TraceID identifies a distributed trace.
+type TraceID [16]byte
+
+// NewTraceID returns a random TraceID.
+func NewTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+
+	return id
+}
+
+// IsValid reports whether the TraceID is not all zeros.
+func (self TraceID) IsValid() bool {
+	return self != TraceID{}
+}
+
+func (self TraceID) String() string {
+	return hex.EncodeToString(self[:])
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// NewSpanID returns a random SpanID.
+func NewSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+
+	return id
+}
+
+// IsValid reports whether the SpanID is not all zeros.
+func (self SpanID) IsValid() bool {
+	return self != SpanID{}
+}
+
+func (self SpanID) String() string {
+	return hex.EncodeToString(self[:])
+}
+
+// TraceFlags are the trace-flags of a TraceParent.
+type TraceFlags byte
+
+// FlagSampled indicates that the caller may have recorded the trace.
+const FlagSampled TraceFlags = 0x01
+
+// Sampled reports whether FlagSampled is set.
+func (self TraceFlags) Sampled() bool {
+	return self&FlagSampled == FlagSampled
+}
+
+// TraceParent is the W3C traceparent header, describing the position of the
+// incoming request in its trace.
+//
+// See https://www.w3.org/TR/trace-context/#traceparent-header
+type TraceParent struct {
+	// Version of the parsed header. Headers are always written as version 00.
+	Version  byte
+	TraceID  TraceID
+	ParentID SpanID
+	Flags    TraceFlags
+}
+
+// NewTraceParent returns a TraceParent starting a new trace.
+func NewTraceParent(sampled bool) TraceParent {
+	traceParent := TraceParent{
+		TraceID:  NewTraceID(),
+		ParentID: NewSpanID(),
+	}
+	if sampled {
+		traceParent.Flags = FlagSampled
+	}
+
+	return traceParent
+}
+
+// IsValid reports whether both the trace ID and parent ID are valid.
+func (self TraceParent) IsValid() bool {
+	return self.TraceID.IsValid() && self.ParentID.IsValid()
+}
+
+// WithParentID returns a copy of the TraceParent with parentID as the parent.
+func (self TraceParent) WithParentID(parentID SpanID) TraceParent {
+	self.ParentID = parentID
+
+	return self
+}
+
+// String returns the version 00 traceparent header value.
+func (self TraceParent) String() string {
+	buffer := make([]byte, 0, traceParentLength)
+	buffer = append(buffer, "00-"...)
+	buffer = appendHex(buffer, self.TraceID[:])
+	buffer = append(buffer, '-')
+	buffer = appendHex(buffer, self.ParentID[:])
+	buffer = append(buffer, '-')
+	buffer = appendHex(buffer, []byte{byte(self.Flags)})
+
+	return string(buffer)
+}
+
+// ParseTraceParent parses a traceparent header value.
+//
+// Headers of future versions are accepted as long as their prefix is a valid
+// version 00 header, as required by the specification.
+func ParseTraceParent(value string) (TraceParent, error) {
+	var traceParent TraceParent
+
+	if len(value) < traceParentLength || len(value) > traceParentMaxLength {
+		return traceParent, fmt.Errorf("%w: invalid length %d", ErrInvalidTraceParent, len(value))
+	}
+
+	version, ok := parseHex(value[0:2])
+	if !ok || version[0] == 0xff {
+		return traceParent, fmt.Errorf("%w: invalid version %q", ErrInvalidTraceParent, value[0:2])
+	}
+	traceParent.Version = version[0]
+
+	if traceParent.Version == traceParentVersion && len(value) != traceParentLength {
+		return traceParent, fmt.Errorf("%w: invalid length %d for version 00", ErrInvalidTraceParent, len(value))
+	}
+	if len(value) > traceParentLength && value[traceParentLength] != '-' {
+		return traceParent, fmt.Errorf("%w: invalid future version format", ErrInvalidTraceParent)
+	}
+
+	if value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return traceParent, fmt.Errorf("%w: invalid delimiters", ErrInvalidTraceParent)
+	}
+
+	traceID, ok := parseHex(value[3:35])
+	if !ok {
+		return traceParent, fmt.Errorf("%w: invalid trace-id %q", ErrInvalidTraceParent, value[3:35])
+	}
+	copy(traceParent.TraceID[:], traceID)
+
+	parentID, ok := parseHex(value[36:52])
+	if !ok {
+		return traceParent, fmt.Errorf("%w: invalid parent-id %q", ErrInvalidTraceParent, value[36:52])
+	}
+	copy(traceParent.ParentID[:], parentID)
+
+	flags, ok := parseHex(value[53:55])
+	if !ok {
+		return traceParent, fmt.Errorf("%w: invalid trace-flags %q", ErrInvalidTraceParent, value[53:55])
+	}
+	traceParent.Flags = TraceFlags(flags[0])
+
+	if !traceParent.TraceID.IsValid() {
+		return traceParent, fmt.Errorf("%w: all zero trace-id", ErrInvalidTraceParent)
+	}
+	if !traceParent.ParentID.IsValid() {
+		return traceParent, fmt.Errorf("%w: all zero parent-id", ErrInvalidTraceParent)
+	}
+
+	return traceParent, nil
+}
+
+const lowerHex = "0123456789abcdef"
+
+func appendHex(buffer []byte, data []byte) []byte {
+	for _, b := range data {
+		buffer = append(buffer, lowerHex[b>>4], lowerHex[b&0x0f])
+	}
+
+	return buffer
+}
+
+// parseHex decodes lowercase hex only, as required by the specification.
+func parseHex(value string) ([]byte, bool) {
+	for index := 0; index < len(value); index++ {
+		if c := value[index]; !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return nil, false
+		}
+	}
+
+	decoded, err := hex.DecodeString(value)
+
+	return decoded, err == nil
+}
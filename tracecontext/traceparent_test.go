@@ -0,0 +1,67 @@
+package tracecontext_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context/tracecontext"
+)
+
+func Test_ParseTraceParent(t *testing.T) {
+	t.Parallel()
+
+	traceParent, err := tracecontext.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.NoError(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceParent.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", traceParent.ParentID.String())
+	assert.True(t, traceParent.Flags.Sampled())
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceParent.String())
+
+	// Future versions may append fields.
+	traceParent, err = tracecontext.ParseTraceParent("cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-what-the-future-holds")
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0xcc), traceParent.Version)
+	assert.False(t, traceParent.Flags.Sampled())
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", traceParent.String())
+}
+
+func Test_ParseTraceParent_invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.future",
+		"cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-" + strings.Repeat("a", 512),
+	} {
+		_, err := tracecontext.ParseTraceParent(value)
+		assert.True(t, errors.Is(err, tracecontext.ErrInvalidTraceParent), value)
+	}
+}
+
+func Test_TraceParent_WithParentID(t *testing.T) {
+	t.Parallel()
+
+	traceParent := tracecontext.NewTraceParent(true)
+	assert.True(t, traceParent.IsValid())
+	assert.True(t, traceParent.Flags.Sampled())
+
+	child := traceParent.WithParentID(tracecontext.NewSpanID())
+	assert.Equal(t, traceParent.TraceID, child.TraceID)
+	assert.NotEqual(t, traceParent.ParentID, child.ParentID)
+
+	parsed, err := tracecontext.ParseTraceParent(child.String())
+	assert.NoError(t, err)
+	assert.Equal(t, child, parsed)
+
+	assert.False(t, tracecontext.TraceParent{}.IsValid())
+}
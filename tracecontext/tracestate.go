@@ -0,0 +1,231 @@
+package tracecontext
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	traceStateMaxMembers     = 32
+	traceStateMaxKeyLength   = 256
+	traceStateMaxValueLength = 256
+	traceStateMaxTenant      = 241
+	traceStateMaxSystem      = 14
+	// Members longer than this are dropped first when truncating.
+	traceStateLargeMember = 128
+)
+
+// TraceStateMember is a single vendor entry of a TraceState.
+type TraceStateMember struct {
+	Key   string
+	Value string
+}
+
+func (self TraceStateMember) String() string {
+	return self.Key + "=" + self.Value
+}
+
+// TraceState is the W3C tracestate header, carrying vendor specific trace
+// information. TraceState is immutable; modifications return a copy.
+//
+// See https://www.w3.org/TR/trace-context/#tracestate-header
+type TraceState struct {
+	members []TraceStateMember
+}
+
+// ParseTraceState parses a tracestate header value.
+func ParseTraceState(value string) (TraceState, error) {
+	var traceState TraceState
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.Trim(entry, " \t")
+		if entry == "" {
+			// Empty list members are allowed.
+			continue
+		}
+
+		separator := strings.IndexByte(entry, '=')
+		if separator < 0 {
+			return TraceState{}, fmt.Errorf("%w: missing '=' in %q", ErrInvalidTraceState, entry)
+		}
+
+		member := TraceStateMember{
+			Key:   entry[:separator],
+			Value: entry[separator+1:],
+		}
+		if err := validateTraceStateMember(member); err != nil {
+			return TraceState{}, err
+		}
+		if _, exists := traceState.Get(member.Key); exists {
+			return TraceState{}, fmt.Errorf("%w: duplicate key %q", ErrInvalidTraceState, member.Key)
+		}
+
+		traceState.members = append(traceState.members, member)
+		if len(traceState.members) > traceStateMaxMembers {
+			return TraceState{}, fmt.Errorf("%w: more than %d members", ErrInvalidTraceState, traceStateMaxMembers)
+		}
+	}
+
+	return traceState, nil
+}
+
+// Len returns the number of members.
+func (self TraceState) Len() int {
+	return len(self.members)
+}
+
+// Members returns a copy of the members, most recently updated first.
+func (self TraceState) Members() []TraceStateMember {
+	return append([]TraceStateMember(nil), self.members...)
+}
+
+// Get the value of key.
+func (self TraceState) Get(key string) (string, bool) {
+	for _, member := range self.members {
+		if member.Key == key {
+			return member.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Insert key with value at the front of the TraceState, replacing any existing
+// value of key, as required when a vendor updates its entry. If the TraceState
+// is full, the last member is dropped.
+func (self TraceState) Insert(key string, value string) (TraceState, error) {
+	member := TraceStateMember{
+		Key:   key,
+		Value: value,
+	}
+	if err := validateTraceStateMember(member); err != nil {
+		return self, err
+	}
+
+	members := make([]TraceStateMember, 0, len(self.members)+1)
+	members = append(members, member)
+	for _, existing := range self.members {
+		if existing.Key != key {
+			members = append(members, existing)
+		}
+	}
+	if len(members) > traceStateMaxMembers {
+		members = members[:traceStateMaxMembers]
+	}
+
+	return TraceState{members: members}, nil
+}
+
+// Delete key from the TraceState.
+func (self TraceState) Delete(key string) TraceState {
+	members := make([]TraceStateMember, 0, len(self.members))
+	for _, existing := range self.members {
+		if existing.Key != key {
+			members = append(members, existing)
+		}
+	}
+
+	return TraceState{members: members}
+}
+
+// String returns the tracestate header value.
+func (self TraceState) String() string {
+	entries := make([]string, len(self.members))
+	for index, member := range self.members {
+		entries[index] = member.String()
+	}
+
+	return strings.Join(entries, ",")
+}
+
+// Truncate returns a TraceState whose header value is at most maxLength
+// characters. Members longer than 128 characters are dropped first, then
+// members are dropped from the end, as recommended by the specification.
+func (self TraceState) Truncate(maxLength int) TraceState {
+	members := append([]TraceStateMember(nil), self.members...)
+
+	length := func() int {
+		total := 0
+		for _, member := range members {
+			total += len(member.Key) + 1 + len(member.Value)
+		}
+		if len(members) > 1 {
+			total += len(members) - 1
+		}
+
+		return total
+	}
+
+	for index := len(members) - 1; index >= 0 && length() > maxLength; index-- {
+		if len(members[index].Key)+1+len(members[index].Value) > traceStateLargeMember {
+			members = append(members[:index], members[index+1:]...)
+		}
+	}
+	for len(members) != 0 && length() > maxLength {
+		members = members[:len(members)-1]
+	}
+
+	return TraceState{members: members}
+}
+
+func validateTraceStateMember(member TraceStateMember) error {
+	if !isTraceStateKey(member.Key) {
+		return fmt.Errorf("%w: invalid key %q", ErrInvalidTraceState, member.Key)
+	}
+	if !isTraceStateValue(member.Value) {
+		return fmt.Errorf("%w: invalid value %q", ErrInvalidTraceState, member.Value)
+	}
+
+	return nil
+}
+
+// isTraceStateKey validates a simple-key or a multi-tenant-key.
+func isTraceStateKey(key string) bool {
+	if len(key) == 0 || len(key) > traceStateMaxKeyLength {
+		return false
+	}
+
+	if at := strings.IndexByte(key, '@'); at >= 0 {
+		tenant, system := key[:at], key[at+1:]
+
+		return len(tenant) != 0 && len(tenant) <= traceStateMaxTenant && isLowerAlphaDigit(tenant[0]) && isKeyChars(tenant[1:]) &&
+			len(system) != 0 && len(system) <= traceStateMaxSystem && isLowerAlpha(system[0]) && isKeyChars(system[1:])
+	}
+
+	return isLowerAlpha(key[0]) && isKeyChars(key[1:])
+}
+
+func isKeyChars(value string) bool {
+	for index := 0; index < len(value); index++ {
+		c := value[index]
+		if !isLowerAlphaDigit(c) && c != '_' && c != '-' && c != '*' && c != '/' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isLowerAlpha(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isLowerAlphaDigit(c byte) bool {
+	return isLowerAlpha(c) || '0' <= c && c <= '9'
+}
+
+// isTraceStateValue validates 0*255(chr) nblk-chr.
+func isTraceStateValue(value string) bool {
+	if len(value) == 0 || len(value) > traceStateMaxValueLength || value[len(value)-1] == ' ' {
+		return false
+	}
+
+	for index := 0; index < len(value); index++ {
+		c := value[index]
+		if c < 0x20 || c > 0x7e || c == ',' || c == '=' {
+			return false
+		}
+	}
+
+	return true
+}
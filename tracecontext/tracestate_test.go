@@ -0,0 +1,85 @@
+package tracecontext_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wspowell/errors"
+
+	"github.com/wspowell/context/tracecontext"
+)
+
+func Test_ParseTraceState(t *testing.T) {
+	t.Parallel()
+
+	traceState, err := tracecontext.ParseTraceState("rojo=00f067aa0ba902b7, ,congo=t61rcWkgMzE,tenant@vendor=x")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, traceState.Len())
+
+	value, ok := traceState.Get("congo")
+	assert.True(t, ok)
+	assert.Equal(t, "t61rcWkgMzE", value)
+
+	assert.Equal(t, "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE,tenant@vendor=x", traceState.String())
+}
+
+func Test_ParseTraceState_invalid(t *testing.T) {
+	t.Parallel()
+
+	members := make([]string, 33)
+	for index := range members {
+		members[index] = fmt.Sprintf("k%d=v", index)
+	}
+
+	for _, value := range []string{
+		"rojo",
+		"Rojo=1",
+		"rojo=1,rojo=2",
+		"rojo=a=b",
+		"@vendor=1",
+		"tenant@=1",
+		"tenant@1vendor=1",
+		strings.Join(members, ","),
+	} {
+		_, err := tracecontext.ParseTraceState(value)
+		assert.True(t, errors.Is(err, tracecontext.ErrInvalidTraceState), value)
+	}
+}
+
+func Test_TraceState_Insert(t *testing.T) {
+	t.Parallel()
+
+	traceState, err := tracecontext.ParseTraceState("rojo=1,congo=2")
+	assert.NoError(t, err)
+
+	updated, err := traceState.Insert("congo", "3")
+	assert.NoError(t, err)
+	assert.Equal(t, "congo=3,rojo=1", updated.String())
+	assert.Equal(t, "rojo=1,congo=2", traceState.String())
+
+	_, err = traceState.Insert("Invalid", "1")
+	assert.True(t, errors.Is(err, tracecontext.ErrInvalidTraceState))
+
+	assert.Equal(t, "congo=3", updated.Delete("rojo").String())
+
+	for index := 0; index < 40; index++ {
+		updated, err = updated.Insert(fmt.Sprintf("k%d", index), "v")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 32, updated.Len())
+	assert.Equal(t, "k39", updated.Members()[0].Key)
+}
+
+func Test_TraceState_Truncate(t *testing.T) {
+	t.Parallel()
+
+	large := "large=" + strings.Repeat("x", 200)
+	traceState, err := tracecontext.ParseTraceState("a=1," + large + ",b=2,c=3")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "a=1,b=2,c=3", traceState.Truncate(20).String())
+	assert.Equal(t, "a=1,b=2", traceState.Truncate(8).String())
+	assert.Equal(t, traceState.String(), traceState.Truncate(512).String())
+}
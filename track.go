@@ -0,0 +1,190 @@
+package context
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// nolint:gochecknoglobals // reason: tracking must be enabled process wide
+var (
+	trackingEnabled int32
+
+	// nextNodeID is the last ID handed out to a tracked Context.
+	nextNodeID uint64
+
+	rootsMutex sync.Mutex
+	roots      = map[*rootCtx]struct{}{}
+)
+
+// EnableTracking of root Contexts created by Background.
+//
+// When enabled, every Context returned by Background records its creation time
+// and the cancelable Contexts derived from it so that live Context trees can be
+// listed using Roots. Tracking adds overhead to Background and should only be
+// enabled for debugging.
+func EnableTracking() {
+	atomic.StoreInt32(&trackingEnabled, 1)
+}
+
+func isTracking() bool {
+	return atomic.LoadInt32(&trackingEnabled) == 1
+}
+
+// Roots returns the tracked root Contexts that still have live cancelable
+// Contexts derived from them, as returned by Background, including their local
+// values. Returns nothing unless EnableTracking was called.
+func Roots() []Context {
+	rootsMutex.Lock()
+	defer rootsMutex.Unlock()
+
+	tracked := make([]Context, 0, len(roots))
+	for root := range roots {
+		tracked = append(tracked, root.localized)
+	}
+
+	return tracked
+}
+
+// CancelNode cancels the tracked Context with the given Node ID.
+// Returns false if no cancelable Context with the ID is live.
+//
+// This is intended for administrative use while debugging only. Canceling a
+// Context outside of its owner's control may leave work half finished.
+func CancelNode(id uint64) bool {
+	for _, root := range Roots() {
+		if child := findNode(root, id); child != nil {
+			child.cancel(true, Canceled)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// findNode searches the cancelable Contexts derived from ctx for id.
+func findNode(ctx Context, id uint64) canceler {
+	for _, child := range childrenOf(ctx) {
+		childCtx, ok := child.(Context)
+		if !ok {
+			continue
+		}
+		if c := cancelCtxOf(childCtx); c != nil && atomic.LoadUint64(&c.id) == id {
+			return child
+		}
+		if found := findNode(childCtx, id); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// nodeID returns the ID of the tracked Context, assigning one on first use.
+func nodeID(id *uint64) uint64 {
+	if current := atomic.LoadUint64(id); current != 0 {
+		return current
+	}
+
+	atomic.CompareAndSwapUint64(id, 0, atomic.AddUint64(&nextNodeID, 1))
+
+	return atomic.LoadUint64(id)
+}
+
+// &rootCtxKey is the key that a rootCtx returns itself for.
+// nolint:gochecknoglobals // reason: matches cancelCtxKey
+var rootCtxKey int
+
+// A rootCtx is a tracked Background Context. It is never canceled, but it keeps
+// a reference to its cancelable children so that they may be listed.
+type rootCtx struct {
+	emptyCtx
+
+	id      uint64
+	created time.Time
+	// localized is the root as returned by Background.
+	localized Context
+
+	mu       sync.Mutex          // protects following fields
+	children map[canceler]uint64 // live cancelable children, values order children by creation
+	childSeq uint64              // sequence of the last child added
+}
+
+func newRootCtx() *rootCtx {
+	return &rootCtx{
+		created: time.Now(),
+	}
+}
+
+func (self *rootCtx) Value(key any) any {
+	if key == &rootCtxKey {
+		return self
+	}
+
+	return nil
+}
+
+func (self *rootCtx) String() string {
+	return "context.Background"
+}
+
+// addChild tracks child and registers the root as live.
+func (self *rootCtx) addChild(child canceler) {
+	self.mu.Lock()
+	if self.children == nil {
+		self.children = make(map[canceler]uint64)
+	}
+	self.childSeq++
+	self.children[child] = self.childSeq
+	first := len(self.children) == 1
+	self.mu.Unlock()
+
+	if first {
+		rootsMutex.Lock()
+		self.mu.Lock()
+		if len(self.children) != 0 {
+			roots[self] = struct{}{}
+		}
+		self.mu.Unlock()
+		rootsMutex.Unlock()
+	}
+}
+
+// removeChild stops tracking child and deregisters the root once no live children remain.
+func (self *rootCtx) removeChild(child canceler) {
+	self.mu.Lock()
+	delete(self.children, child)
+	last := len(self.children) == 0
+	self.mu.Unlock()
+
+	if last {
+		rootsMutex.Lock()
+		self.mu.Lock()
+		if len(self.children) == 0 {
+			delete(roots, self)
+		}
+		self.mu.Unlock()
+		rootsMutex.Unlock()
+	}
+}
+
+// trackedRoot returns the rootCtx of ctx if ctx is a root as returned by Background.
+func trackedRoot(ctx Context) (*rootCtx, bool) {
+	if local, ok := ctx.(*localCtx); ok {
+		ctx = local.Context
+	}
+	root, ok := ctx.(*rootCtx)
+
+	return root, ok
+}
+
+// parentRootCtx returns the tracked root of parent if parent can never be canceled.
+func parentRootCtx(parent Context) (*rootCtx, bool) {
+	if parent.Done() != nil {
+		return nil, false
+	}
+	root, ok := parent.Value(&rootCtxKey).(*rootCtx)
+
+	return root, ok
+}
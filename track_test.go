@@ -0,0 +1,74 @@
+package context_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+)
+
+func isRoot(id uint64) bool {
+	for _, root := range context.Roots() {
+		for _, child := range context.Tree(root).Children {
+			if child.ID == id {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Tracking is process wide, the tests enabling it do not run in parallel so
+// that it is enabled before any parallel test runs.
+
+func Test_Roots(t *testing.T) {
+	context.EnableTracking()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	id := context.Tree(ctx).ID
+	assert.NotZero(t, id)
+	assert.True(t, isRoot(id))
+
+	cancel()
+	assert.False(t, isRoot(id))
+}
+
+func Test_Roots_locals(t *testing.T) {
+	context.EnableTracking()
+
+	ctx := context.Background()
+	context.WithLocalValue(ctx, localContextKey{}, localValue)
+
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	id := context.Tree(child).ID
+
+	var root context.Node
+	for _, tracked := range context.Roots() {
+		if node := context.Tree(tracked); len(node.Children) != 0 && node.Children[0].ID == id {
+			root = node
+		}
+	}
+
+	assert.Equal(t, "context.Background", root.Kind)
+	assert.NotZero(t, root.ID)
+	assert.Equal(t, []string{"context_test.localContextKey"}, root.Locals)
+	assert.Equal(t, context.Goroutine(ctx), root.Goroutine)
+}
+
+func Test_CancelNode(t *testing.T) {
+	context.EnableTracking()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	child, childCancel := context.WithCancel(ctx)
+	defer childCancel()
+
+	assert.True(t, context.CancelNode(context.Tree(child).ID))
+	assert.ErrorIs(t, child.Err(), context.Canceled)
+	assert.Nil(t, ctx.Err())
+	assert.False(t, context.CancelNode(context.Tree(child).ID))
+}
@@ -0,0 +1,328 @@
+package context
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Node describes a Context and the live cancelable Contexts derived from it.
+//
+// Children are discovered through the cancelation bookkeeping of the Context
+// tree, therefore only children created by WithCancel, WithDeadline, or
+// WithTimeout that have not been canceled are included. Values and locals
+// added after a child was derived are reported on the grandchildren that
+// observe them.
+type Node struct {
+	// ID of the Context, usable with CancelNode. Only cancelable and tracked
+	// root Contexts have an ID.
+	ID uint64 `json:"id,omitempty"`
+	// Kind of the Context layer, such as "WithCancel" or "WithDeadline".
+	Kind string `json:"kind"`
+	// Name is the NamePath of the Context, if named.
+	Name string `json:"name,omitempty"`
+	// Deadline of the Context, if one is set.
+	Deadline *time.Time `json:"deadline,omitempty"`
+	// Remaining time until the deadline at the time the Node was created.
+	Remaining time.Duration `json:"remaining,omitempty"`
+	// Age of a tracked root Context.
+	Age time.Duration `json:"age,omitempty"`
+	// Err is the cancelation error of the Context, if canceled.
+	Err string `json:"err,omitempty"`
+	// Values contains the key types of the immutable values added between this
+	// Node and its parent Node, innermost first.
+	Values []string `json:"values,omitempty"`
+	// Locals contains the key types of the local values visible to this Node.
+	Locals []string `json:"locals,omitempty"`
+	// Goroutine is the ID of the goroutine that owns the local values.
+	// Always zero in release builds.
+	Goroutine uint64 `json:"goroutine,omitempty"`
+	// Children derived from this Node.
+	Children []Node `json:"children,omitempty"`
+}
+
+// Tree returns a snapshot of ctx and every live cancelable Context derived from it.
+func Tree(ctx Context) Node {
+	node := newNode(ctx, nil)
+	node.Children = treeChildren(ctx)
+
+	return node
+}
+
+// Goroutine returns the ID of the goroutine ctx is localized to, or zero if
+// ctx is not localized. Always zero in release builds.
+func Goroutine(ctx Context) uint64 {
+	if local, ok := ctx.Value(localsKey{}).(*localCtx); ok {
+		return local.goroutine()
+	}
+
+	return 0
+}
+
+// WriteText renders the Node and its children as an indented text tree.
+func (self Node) WriteText(w io.Writer) error {
+	var builder strings.Builder
+	self.writeText(&builder, "", "")
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err // nolint:wrapcheck // reason: passthrough of writer error
+}
+
+// String renders the Node and its children as an indented text tree.
+func (self Node) String() string {
+	var builder strings.Builder
+	self.writeText(&builder, "", "")
+
+	return builder.String()
+}
+
+// WriteJSON renders the Node and its children as JSON.
+func (self Node) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(self) // nolint:wrapcheck // reason: passthrough of encoder error
+}
+
+func (self Node) writeText(builder *strings.Builder, prefix string, childPrefix string) {
+	builder.WriteString(prefix)
+	builder.WriteString(self.Kind)
+	if self.Name != "" {
+		builder.WriteString(" name=")
+		builder.WriteString(strconv.Quote(self.Name))
+	}
+	if self.ID != 0 {
+		builder.WriteString(" id=")
+		builder.WriteString(strconv.FormatUint(self.ID, 10))
+	}
+	if self.Age != 0 {
+		builder.WriteString(" age=")
+		builder.WriteString(self.Age.String())
+	}
+	if self.Deadline != nil {
+		builder.WriteString(" deadline=")
+		builder.WriteString(self.Deadline.Format(time.RFC3339Nano))
+		builder.WriteString(" remaining=")
+		builder.WriteString(self.Remaining.String())
+	}
+	if self.Err != "" {
+		builder.WriteString(" err=")
+		builder.WriteString(strconv.Quote(self.Err))
+	}
+	if self.Goroutine != 0 {
+		builder.WriteString(" goroutine=")
+		builder.WriteString(strconv.FormatUint(self.Goroutine, 10))
+	}
+	if len(self.Values) != 0 {
+		builder.WriteString(" values=[")
+		builder.WriteString(strings.Join(self.Values, " "))
+		builder.WriteString("]")
+	}
+	if len(self.Locals) != 0 {
+		builder.WriteString(" locals=[")
+		builder.WriteString(strings.Join(self.Locals, " "))
+		builder.WriteString("]")
+	}
+	builder.WriteString("\n")
+
+	for index, child := range self.Children {
+		if index == len(self.Children)-1 {
+			child.writeText(builder, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			child.writeText(builder, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+// newNode describes ctx. Immutable values are collected walking up the chain
+// until stop is reached.
+func newNode(ctx Context, stop *cancelCtx) Node {
+	node := Node{
+		Kind: contextKind(ctx),
+		Name: NamePath(ctx),
+	}
+
+	if c := cancelCtxOf(ctx); c != nil {
+		node.ID = nodeID(&c.id)
+	} else if root, ok := trackedRoot(ctx); ok {
+		node.ID = nodeID(&root.id)
+		node.Age = time.Since(root.created)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		node.Deadline = &deadline
+		node.Remaining = time.Until(deadline)
+	}
+
+	if err := ctx.Err(); err != nil {
+		node.Err = err.Error()
+	}
+
+	for current, ok := ctx, true; ok; current, ok = parentContext(current) {
+		if c := cancelCtxOf(current); c != nil && c == stop {
+			break
+		}
+		if value, isValue := current.(*valueCtx); isValue {
+			node.Values = append(node.Values, reflect.TypeOf(value.key).String())
+		}
+	}
+
+	if local, ok := ctx.Value(localsKey{}).(*localCtx); ok {
+		local.localsMutex.RLock()
+		for key, value := range local.localValues {
+			if value != nil {
+				node.Locals = append(node.Locals, reflect.TypeOf(key).String())
+			}
+		}
+		local.localsMutex.RUnlock()
+		sort.Strings(node.Locals)
+		node.Goroutine = local.goroutine()
+	}
+
+	return node
+}
+
+// treeChildren returns the live cancelable children derived from ctx.
+func treeChildren(ctx Context) []Node {
+	parent, _ := ctx.Value(&cancelCtxKey).(*cancelCtx)
+	children := childrenOf(ctx)
+
+	nodes := make([]Node, 0, len(children))
+	for _, child := range children {
+		childCtx, ok := child.(Context)
+		if !ok {
+			continue
+		}
+
+		node := newNode(childCtx, parent)
+		node.Children = treeChildren(childCtx)
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// childrenOf returns a snapshot of the live cancelable children derived from
+// ctx, in the order they were created.
+func childrenOf(ctx Context) []canceler {
+	type orderedChild struct {
+		child canceler
+		seq   uint64
+	}
+
+	var ordered []orderedChild
+
+	if parent, ok := ctx.Value(&cancelCtxKey).(*cancelCtx); ok {
+		parent.mu.Lock()
+		ordered = make([]orderedChild, 0, len(parent.children))
+		for child, seq := range parent.children {
+			if childCtx, ok := child.(Context); ok && isDerivedFrom(childCtx, ctx, parent) {
+				ordered = append(ordered, orderedChild{child: child, seq: seq})
+			}
+		}
+		parent.mu.Unlock()
+	} else if root, ok := parentRootCtx(ctx); ok {
+		root.mu.Lock()
+		ordered = make([]orderedChild, 0, len(root.children))
+		for child, seq := range root.children {
+			if childCtx, ok := child.(Context); ok && isDerivedFrom(childCtx, ctx, nil) {
+				ordered = append(ordered, orderedChild{child: child, seq: seq})
+			}
+		}
+		root.mu.Unlock()
+	}
+
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].seq < ordered[j].seq
+	})
+
+	children := make([]canceler, len(ordered))
+	for index, entry := range ordered {
+		children[index] = entry.child
+	}
+
+	return children
+}
+
+// isDerivedFrom reports whether ctx passes through ancestor before reaching the cancelCtx stop.
+func isDerivedFrom(ctx Context, ancestor Context, stop *cancelCtx) bool {
+	for current, ok := parentContext(ctx); ok; current, ok = parentContext(current) {
+		if current == ancestor {
+			return true
+		}
+		if c := cancelCtxOf(current); c != nil && c == stop {
+			return false
+		}
+	}
+
+	return false
+}
+
+// parentContext returns the Context that ctx was derived from.
+// Returns false if ctx is not a known Context implementation or has no parent.
+func parentContext(ctx Context) (Context, bool) {
+	switch c := ctx.(type) {
+	case *cancelCtx:
+		return c.Context, true
+	case *timerCtx:
+		return c.cancelCtx.Context, true
+	case *signalCtx:
+		return c.cancelCtx.Context, true
+	case *valueCtx:
+		return c.Context, true
+	case *localCtx:
+		return c.Context, true
+	case *nameCtx:
+		return c.Context, true
+	case *withoutCancelCtx:
+		return c.Context, true
+	}
+
+	return nil, false
+}
+
+// cancelCtxOf returns the cancelCtx implementing ctx, if any.
+func cancelCtxOf(ctx Context) *cancelCtx {
+	switch c := ctx.(type) {
+	case *cancelCtx:
+		return c
+	case *timerCtx:
+		return &c.cancelCtx
+	case *signalCtx:
+		return &c.cancelCtx
+	}
+
+	return nil
+}
+
+// contextKind returns the name of the outermost layer of ctx.
+func contextKind(ctx Context) string {
+	switch c := ctx.(type) {
+	case *cancelCtx:
+		return "WithCancel"
+	case *timerCtx:
+		return "WithDeadline"
+	case *signalCtx:
+		return "WithSignals"
+	case *valueCtx:
+		return "WithValue"
+	case *localCtx:
+		if root, ok := c.Context.(*rootCtx); ok {
+			return root.String()
+		}
+
+		return "Localize"
+	case *nameCtx:
+		return "WithName"
+	case *withoutCancelCtx:
+		return "WithoutCancel"
+	case *emptyCtx:
+		return c.String()
+	case *rootCtx:
+		return c.String()
+	}
+
+	return reflect.TypeOf(ctx).String()
+}
@@ -0,0 +1,132 @@
+package context_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+)
+
+func Test_Tree(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	valueCtx := context.WithValue(ctx, immutableContextKey{}, immutableValue)
+	context.WithLocalValue(valueCtx, localContextKey{}, localValue)
+
+	deadlineCtx, deadlineCancel := context.WithTimeout(valueCtx, time.Minute)
+	defer deadlineCancel()
+
+	_, childCancel := context.WithCancel(deadlineCtx)
+	defer childCancel()
+
+	// Sibling not derived from valueCtx.
+	_, siblingCancel := context.WithCancel(ctx)
+	defer siblingCancel()
+
+	node := context.Tree(valueCtx)
+	assert.Equal(t, "WithValue", node.Kind)
+	assert.Nil(t, node.Deadline)
+	assert.Equal(t, []string{"context_test.immutableContextKey"}, node.Values)
+	assert.Equal(t, []string{"context_test.localContextKey"}, node.Locals)
+
+	assert.Len(t, node.Children, 1)
+	deadlineNode := node.Children[0]
+	assert.Equal(t, "WithDeadline", deadlineNode.Kind)
+	assert.NotNil(t, deadlineNode.Deadline)
+	assert.Greater(t, deadlineNode.Remaining, time.Duration(0))
+	assert.Equal(t, []string{"context_test.immutableContextKey"}, deadlineNode.Values)
+
+	assert.Len(t, deadlineNode.Children, 1)
+	assert.Equal(t, "WithCancel", deadlineNode.Children[0].Kind)
+	assert.Empty(t, deadlineNode.Children[0].Values)
+
+	assert.Len(t, context.Tree(ctx).Children, 2)
+
+	childCancel()
+	assert.Empty(t, context.Tree(valueCtx).Children[0].Children)
+}
+
+func Test_Tree_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	node := context.Tree(ctx)
+	assert.Equal(t, "context canceled", node.Err)
+	assert.Empty(t, node.Children)
+}
+
+func Test_Node_WriteText(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, firstCancel := context.WithTimeout(ctx, time.Minute)
+	defer firstCancel()
+
+	_, secondCancel := context.WithTimeout(ctx, time.Minute)
+	defer secondCancel()
+
+	var buffer bytes.Buffer
+	assert.Nil(t, context.Tree(ctx).WriteText(&buffer))
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "WithCancel"))
+	assert.True(t, strings.HasPrefix(lines[1], "├── WithDeadline id="))
+	assert.True(t, strings.HasPrefix(lines[2], "└── WithDeadline id="))
+	assert.True(t, strings.HasPrefix(context.Tree(ctx).String(), lines[0]))
+}
+
+func Test_Node_WriteJSON(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	valueCtx := context.WithValue(ctx, immutableContextKey{}, immutableValue)
+	_, childCancel := context.WithTimeout(valueCtx, time.Minute)
+	defer childCancel()
+
+	var buffer bytes.Buffer
+	assert.Nil(t, context.Tree(ctx).WriteJSON(&buffer))
+
+	var node context.Node
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &node))
+	assert.Equal(t, "WithCancel", node.Kind)
+	assert.Len(t, node.Children, 1)
+	assert.Equal(t, "WithDeadline", node.Children[0].Kind)
+	assert.NotNil(t, node.Children[0].Deadline)
+	assert.Equal(t, []string{"context_test.immutableContextKey"}, node.Children[0].Values)
+}
+
+func Test_Tree_children_ordered(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	names := []string{"first", "second", "third", "fourth", "fifth"}
+	for _, name := range names {
+		_, childCancel := context.WithCancel(context.WithName(ctx, name))
+		defer childCancel()
+	}
+
+	for run := 0; run < 10; run++ {
+		children := context.Tree(ctx).Children
+		assert.Len(t, children, len(names))
+		for index, child := range children {
+			assert.Equal(t, names[index], child.Name)
+		}
+	}
+}
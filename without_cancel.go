@@ -0,0 +1,53 @@
+package context
+
+import (
+	"time"
+)
+
+// WithoutCancel returns a copy of parent that is not canceled when parent is
+// canceled. The returned context returns no Deadline or Err, and its Done
+// channel is nil.
+//
+// Values of parent remain visible, including local values, which remain bound
+// to the goroutine of parent. Localize the returned context before using it in
+// another goroutine.
+func WithoutCancel(parent Context) Context {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+
+	return &withoutCancelCtx{
+		Context: parent,
+	}
+}
+
+// A withoutCancelCtx detaches from the cancellation of its parent while
+// keeping its values.
+type withoutCancelCtx struct {
+	Context
+}
+
+func (*withoutCancelCtx) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (*withoutCancelCtx) Done() <-chan struct{} {
+	return nil
+}
+
+func (*withoutCancelCtx) Err() error {
+	return nil
+}
+
+func (c *withoutCancelCtx) Value(key any) any {
+	if key == &cancelCtxKey {
+		// Not canceled by the parent, so not a child of its cancelCtx.
+		return nil
+	}
+
+	return c.Context.Value(key)
+}
+
+func (c *withoutCancelCtx) String() string {
+	return contextName(c.Context) + ".WithoutCancel"
+}
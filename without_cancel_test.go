@@ -0,0 +1,41 @@
+package context_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wspowell/context"
+)
+
+func Test_WithoutCancel(t *testing.T) {
+	t.Parallel()
+
+	parent, cancel := context.WithTimeout(context.WithName(context.Background(), "request"), time.Minute)
+	parent = context.WithValue(parent, immutableContextKey{}, immutableValue)
+	context.WithLocalValue(parent, localContextKey{}, localValue)
+
+	ctx := context.WithoutCancel(parent)
+	child, childCancel := context.WithCancel(ctx)
+	defer childCancel()
+
+	cancel()
+
+	assert.Nil(t, ctx.Done())
+	assert.Nil(t, ctx.Err())
+	assert.Nil(t, child.Err())
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline)
+	assert.Equal(t, immutableValue, ctx.Value(immutableContextKey{}))
+	assert.Equal(t, localValue, ctx.Value(localContextKey{}))
+	assert.Equal(t, "request", context.NamePath(ctx))
+	assert.Equal(t, "WithoutCancel", context.Tree(ctx).Kind)
+
+	childCancel()
+	assert.ErrorIs(t, child.Err(), context.Canceled)
+
+	assert.Panics(t, func() {
+		context.WithoutCancel(nil) // nolint:staticcheck // reason: testing nil parent
+	})
+}